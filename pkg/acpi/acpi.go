@@ -0,0 +1,246 @@
+// Package acpi provides utilities to measure the ACPI tables exposed by the firmware.
+package acpi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	acpiTablesDir     = "/sys/firmware/acpi/tables"
+	dynamicTablesDir  = "dynamic"
+	tableHeaderSize   = 36
+	dmarFlagsOffset   = 37
+	ivrsIVInfoOffset  = 36
+	dmarDMAOptIn      = 0x04 // DMA_CTRL_PLATFORM_OPT_IN_FLAG
+	ivrsDMARemap      = 0x02 // IVinfo DMA remap support
+	signatureDMAR     = "DMAR"
+	signatureIVRS     = "IVRS"
+	signatureWPBT     = "WPBT"
+	signatureFACS     = "FACS"
+	ivrsIVInfoSize    = 4
+	dmarFlagsMinBytes = dmarFlagsOffset + 1
+)
+
+// facsMutableFields lists the offset and size of the FACS fields the firmware and the OS write at runtime:
+// the firmware waking vector, the global lock, the extended firmware waking vector and the OSPM flags.
+//
+//nolint:gochecknoglobals
+var facsMutableFields = [][2]int{
+	{12, 4},
+	{16, 4},
+	{24, 8},
+	{36, 4},
+}
+
+// riskyTables lists the table signatures that allow firmware to inject code into the running system.
+//
+//nolint:gochecknoglobals
+var riskyTables = map[string]bool{
+	signatureWPBT: true, // Windows Platform Binary Table
+}
+
+// Table represents the header and digest of a single ACPI table.
+type Table struct {
+	Name        string `yaml:"name"`
+	Signature   string `yaml:"signature"`
+	Length      uint32 `yaml:"length"`
+	Revision    uint8  `yaml:"revision"`
+	OEMID       string `yaml:"oemId"`
+	OEMTableID  string `yaml:"oemTableId"`
+	OEMRevision uint32 `yaml:"oemRevision"`
+	CreatorID   string `yaml:"creatorId"`
+	Dynamic     bool   `yaml:"dynamic"`
+	Hash        string `yaml:"hash"`
+}
+
+// State represents the measured ACPI tables and the DMA protection they advertise.
+type State struct {
+	Tables        []Table `yaml:"tables"`
+	DMAProtection bool    `yaml:"dmaProtection"`
+}
+
+// Attestable implements the report.Attestable interface for ACPI tables.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "acpi-tables"
+}
+
+// Measure returns the measurement of the ACPI tables.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetACPIState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get ACPI tables: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ACPI tables: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the ACPI tables.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"tables_count":   strconv.Itoa(len(a.state.Tables)),
+		"dma_protection": strconv.FormatBool(a.state.DMAProtection),
+		"timestamp":      a.timestamp,
+	}
+
+	signatures := make(map[string]bool)
+	risky := make([]string, 0)
+	dynamic := 0
+
+	for i, table := range a.state.Tables {
+		prefix := fmt.Sprintf("table_%d_", i)
+		evidence[prefix+"name"] = table.Name
+		evidence[prefix+"signature"] = table.Signature
+		evidence[prefix+"length"] = strconv.FormatUint(uint64(table.Length), 10)
+		evidence[prefix+"revision"] = strconv.Itoa(int(table.Revision))
+		evidence[prefix+"oem_id"] = table.OEMID
+		evidence[prefix+"oem_table_id"] = table.OEMTableID
+		evidence[prefix+"oem_revision"] = strconv.FormatUint(uint64(table.OEMRevision), 10)
+		evidence[prefix+"creator_id"] = table.CreatorID
+		evidence[prefix+"hash"] = table.Hash
+
+		signatures[table.Signature] = true
+
+		if riskyTables[table.Signature] {
+			risky = append(risky, table.Name)
+		}
+
+		if table.Dynamic {
+			dynamic++
+		}
+	}
+
+	evidence["risky_tables"] = strings.Join(risky, ",")
+	evidence["dynamic_tables_count"] = strconv.Itoa(dynamic)
+	evidence["wpbt_present"] = strconv.FormatBool(signatures[signatureWPBT])
+	evidence["dmar_present"] = strconv.FormatBool(signatures[signatureDMAR])
+	evidence["ivrs_present"] = strconv.FormatBool(signatures[signatureIVRS])
+
+	return evidence, nil
+}
+
+// GetACPIState hashes every ACPI table and checks whether DMAR or IVRS advertise DMA protection.
+func GetACPIState() (*State, error) {
+	state := &State{
+		Tables: make([]Table, 0),
+	}
+
+	for _, dir := range []string{"", dynamicTablesDir} {
+		err := readTables(dir, state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+func readTables(dir string, state *State) error {
+	entries, err := os.ReadDir(filepath.Join(acpiTablesDir, dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read ACPI tables directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := filepath.Join(dir, entry.Name())
+
+		//nolint:gosec // ACPI table path is controlled
+		data, err := os.ReadFile(filepath.Join(acpiTablesDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read ACPI table %s: %w", name, err)
+		}
+
+		if len(data) < tableHeaderSize {
+			continue
+		}
+
+		signature := strings.TrimSpace(utils.CString(data[0:4]))
+
+		// The FACS has no standard table header and is written at runtime, so only its stable fields are hashed.
+		if signature == signatureFACS {
+			state.Tables = append(state.Tables, Table{
+				Name:      name,
+				Signature: signature,
+				Length:    binary.LittleEndian.Uint32(data[4:8]),
+				Dynamic:   dir == dynamicTablesDir,
+				Hash:      utils.EncodeMeasurement(stableFACS(data)),
+			})
+
+			continue
+		}
+
+		state.Tables = append(state.Tables, Table{
+			Name:        name,
+			Signature:   signature,
+			Length:      binary.LittleEndian.Uint32(data[4:8]),
+			Revision:    data[8],
+			OEMID:       strings.TrimSpace(utils.CString(data[10:16])),
+			OEMTableID:  strings.TrimSpace(utils.CString(data[16:24])),
+			OEMRevision: binary.LittleEndian.Uint32(data[24:28]),
+			CreatorID:   strings.TrimSpace(utils.CString(data[28:32])),
+			Dynamic:     dir == dynamicTablesDir,
+			Hash:        utils.EncodeMeasurement(data),
+		})
+
+		if hasDMAProtection(signature, data) {
+			state.DMAProtection = true
+		}
+	}
+
+	return nil
+}
+
+// stableFACS returns a copy of the FACS with the fields written at runtime zeroed.
+func stableFACS(data []byte) []byte {
+	stable := append([]byte{}, data...)
+
+	for _, field := range facsMutableFields {
+		offset, size := field[0], field[1]
+		if offset+size <= len(stable) {
+			clear(stable[offset : offset+size])
+		}
+	}
+
+	return stable
+}
+
+// hasDMAProtection reports whether the DMAR or IVRS table advertises DMA protection.
+func hasDMAProtection(signature string, data []byte) bool {
+	switch signature {
+	case signatureDMAR:
+		return len(data) >= dmarFlagsMinBytes && data[dmarFlagsOffset]&dmarDMAOptIn != 0
+	case signatureIVRS:
+		return len(data) >= ivrsIVInfoOffset+ivrsIVInfoSize &&
+			binary.LittleEndian.Uint32(data[ivrsIVInfoOffset:])&ivrsDMARemap != 0
+	default:
+		return false
+	}
+}
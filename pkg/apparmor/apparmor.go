@@ -2,22 +2,49 @@
 package apparmor
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
 )
 
 const (
-	appArmorEnabledPath = "/sys/module/apparmor/parameters/enabled"
+	appArmorEnabledPath    = "/sys/module/apparmor/parameters/enabled"
+	appArmorProfilesPath   = "/sys/kernel/security/apparmor/profiles"
+	appArmorPolicyProfiles = "/sys/kernel/security/apparmor/policy/profiles"
+	profileModeSeparator   = " ("
+	profileModeSuffix      = ")"
+	policyProfileNameFile  = "name"
+	policyProfileHashFile  = "sha256"
+	policyProfileRawData   = "raw_data"
+	policyChildProfiles    = "profiles"
 )
 
+// Profile represents a loaded AppArmor profile and its mode.
+type Profile struct {
+	Name string `yaml:"name"`
+	Mode string `yaml:"mode"`
+	Hash string `yaml:"hash"`
+}
+
+// State represents the AppArmor status and the loaded policy.
+type State struct {
+	Enabled  bool      `yaml:"enabled"`
+	Profiles []Profile `yaml:"profiles"`
+}
+
 // Attestable implements the report.Attestable interface for AppArmor.
 type Attestable struct {
-	enabled   bool
-	timestamp string
+	state        *State
+	policyDigest string
+	timestamp    string
 }
 
 // Name returns the name of the attestable component.
@@ -25,25 +52,155 @@ func (a *Attestable) Name() string {
 	return "apparmor"
 }
 
-// Measure returns the measurement of the AppArmor status.
+// Measure returns the measurement of the AppArmor status and the loaded policy.
 func (a *Attestable) Measure() (string, error) {
 	enabled, err := isAppArmorEnabled()
 	if err != nil {
 		return "", fmt.Errorf("failed to determine AppArmor status: %w", err)
 	}
 
+	state := &State{
+		Enabled:  enabled,
+		Profiles: make([]Profile, 0),
+	}
+
+	if enabled {
+		state.Profiles, err = GetProfiles()
+		if err != nil {
+			return "", fmt.Errorf("failed to get AppArmor profiles: %w", err)
+		}
+	}
+
+	profiles, err := yaml.Marshal(state.Profiles)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal AppArmor profiles: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal AppArmor state: %w", err)
+	}
+
 	a.timestamp = utils.UnixNowString()
-	a.enabled = enabled
+	a.state = state
+	a.policyDigest = utils.EncodeMeasurement(profiles)
 
-	return utils.BoolToMeasurement(enabled), nil
+	return utils.EncodeMeasurement(data), nil
 }
 
-// Evidence returns metadata about the AppArmor status.
+// Evidence returns metadata about the AppArmor status and the loaded profiles.
 func (a *Attestable) Evidence() (map[string]string, error) {
-	return map[string]string{
-		a.Name():    strconv.FormatBool(a.enabled),
-		"timestamp": a.timestamp,
-	}, nil
+	evidence := map[string]string{
+		a.Name():         strconv.FormatBool(a.state.Enabled),
+		"profiles_count": strconv.Itoa(len(a.state.Profiles)),
+		"policy_digest":  a.policyDigest,
+		"timestamp":      a.timestamp,
+	}
+
+	modes := make(map[string]int)
+
+	for i, profile := range a.state.Profiles {
+		prefix := fmt.Sprintf("profile_%d_", i)
+		evidence[prefix+"name"] = profile.Name
+		evidence[prefix+"mode"] = profile.Mode
+		evidence[prefix+"hash"] = profile.Hash
+		modes[profile.Mode]++
+	}
+
+	for _, mode := range []string{"enforce", "complain", "kill", "unconfined"} {
+		evidence[mode+"_count"] = strconv.Itoa(modes[mode])
+	}
+
+	return evidence, nil
+}
+
+// GetProfiles returns the loaded AppArmor profiles sorted by name, with the hash of their policy
+// when the kernel exposes it.
+func GetProfiles() ([]Profile, error) {
+	file, err := os.Open(appArmorProfilesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Profile{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", appArmorProfilesPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hashes := getPolicyHashes()
+	profiles := make([]Profile, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		index := strings.LastIndex(line, profileModeSeparator)
+		if index < 0 || !strings.HasSuffix(line, profileModeSuffix) {
+			continue
+		}
+
+		name := line[:index]
+
+		profiles = append(profiles, Profile{
+			Name: name,
+			Mode: strings.TrimSuffix(line[index+len(profileModeSeparator):], profileModeSuffix),
+			Hash: hashes[name],
+		})
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", appArmorProfilesPath, err)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+// getPolicyHashes returns the policy hash of each profile in the policy tree, keyed by profile name.
+// The kernel provides the hash itself when built with policy hashing, otherwise the raw policy is hashed.
+func getPolicyHashes() map[string]string {
+	hashes := make(map[string]string)
+
+	collectPolicyHashes(appArmorPolicyProfiles, hashes)
+
+	return hashes
+}
+
+// collectPolicyHashes adds the hashes of the profiles in the directory to the map, then descends into
+// their child profiles, whose names are the hierarchical "parent//child" names listed in the profiles file.
+func collectPolicyHashes(path string, hashes map[string]string) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		dir := filepath.Join(path, entry.Name())
+
+		name := utils.ReadSysfsValue(filepath.Join(dir, policyProfileNameFile))
+		if name == "" {
+			continue
+		}
+
+		hash := utils.ReadSysfsValue(filepath.Join(dir, policyProfileHashFile))
+		if hash == "" {
+			// raw_data is a symlink to the directory of the loaded policy, which holds the blob in raw_data.
+			//nolint:gosec // Policy path is controlled
+			raw, err := os.ReadFile(filepath.Join(dir, policyProfileRawData, policyProfileRawData))
+			if err == nil {
+				hash = utils.EncodeMeasurement(raw)
+			}
+		}
+
+		hashes[name] = hash
+
+		collectPolicyHashes(filepath.Join(dir, policyChildProfiles), hashes)
+	}
 }
 
 func isAppArmorEnabled() (bool, error) {
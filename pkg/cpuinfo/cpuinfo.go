@@ -0,0 +1,71 @@
+// Package cpuinfo provides functionality to parse the /proc/cpuinfo file.
+package cpuinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	procCPUInfoPath = "/proc/cpuinfo"
+	flagsKey        = "flags"
+)
+
+// Processor represents a single processor entry of /proc/cpuinfo as key-value pairs.
+type Processor map[string]string
+
+// Flags returns the set of CPU flags reported for the processor.
+func (p Processor) Flags() map[string]bool {
+	flags := make(map[string]bool)
+
+	for _, flag := range strings.Fields(p[flagsKey]) {
+		flags[flag] = true
+	}
+
+	return flags
+}
+
+// ParseProcCPUInfo reads and parses the /proc/cpuinfo file into one entry per processor.
+func ParseProcCPUInfo() ([]Processor, error) {
+	file, err := os.Open(procCPUInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", procCPUInfoPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	processors := make([]Processor, 0)
+	current := make(Processor)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				processors = append(processors, current)
+				current = make(Processor)
+			}
+
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, ":")
+		current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", procCPUInfoPath, err)
+	}
+
+	if len(current) > 0 {
+		processors = append(processors, current)
+	}
+
+	return processors, nil
+}
@@ -0,0 +1,184 @@
+// Package devicemapper provides utilities to inspect the device-mapper devices of the machine.
+package devicemapper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	sysBlockDir         = "/sys/block"
+	controlDevicePath   = "/dev/mapper/control"
+	initialBufferSize   = 16 * 1024
+	maximumBufferSize   = 1024 * 1024
+	dmDevicePrefix      = "dm-"
+	dmVersionMinor      = 0
+	dmVersionPatchLevel = 0
+)
+
+// Device represents a device-mapper device as exposed under /sys/block.
+type Device struct {
+	Kernel string `yaml:"kernel"` // kernel name, e.g. dm-0
+	Name   string `yaml:"name"`   // device-mapper name, e.g. vroot
+	UUID   string `yaml:"uuid"`
+	Dev    string `yaml:"dev"` // major:minor
+}
+
+// Target represents a single target of a device-mapper table.
+type Target struct {
+	Start  uint64 `yaml:"start"`
+	Length uint64 `yaml:"length"`
+	Type   string `yaml:"type"`
+	Params string `yaml:"params"`
+}
+
+// ListDevices returns the device-mapper devices found under /sys/block.
+func ListDevices() ([]Device, error) {
+	entries, err := os.ReadDir(sysBlockDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Device{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sysBlockDir, err)
+	}
+
+	devices := make([]Device, 0)
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), dmDevicePrefix) {
+			continue
+		}
+
+		dir := filepath.Join(sysBlockDir, entry.Name())
+
+		devices = append(devices, Device{
+			Kernel: entry.Name(),
+			Name:   utils.ReadSysfsValue(filepath.Join(dir, "dm", "name")),
+			UUID:   utils.ReadSysfsValue(filepath.Join(dir, "dm", "uuid")),
+			Dev:    utils.ReadSysfsValue(filepath.Join(dir, "dev")),
+		})
+	}
+
+	return devices, nil
+}
+
+// Table returns the loaded table of the named device-mapper device.
+func Table(name string) ([]Target, error) {
+	return tableStatus(name, unix.DM_STATUS_TABLE_FLAG)
+}
+
+// Status returns the status of each target of the named device-mapper device.
+func Status(name string) ([]Target, error) {
+	return tableStatus(name, 0)
+}
+
+func tableStatus(name string, flags uint32) ([]Target, error) {
+	control, err := os.OpenFile(controlDevicePath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", controlDevicePath, err)
+	}
+
+	defer func() {
+		_ = control.Close()
+	}()
+
+	for size := initialBufferSize; size <= maximumBufferSize; size *= 2 {
+		header, buf, err := ioctl(control, name, flags, size)
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Flags&unix.DM_BUFFER_FULL_FLAG != 0 {
+			continue
+		}
+
+		return parseTargets(header, buf)
+	}
+
+	return nil, fmt.Errorf("%w: table of %s exceeds %d bytes", ErrInvalidResponse, name, maximumBufferSize)
+}
+
+func ioctl(control *os.File, name string, flags uint32, size int) (*unix.DmIoctl, []byte, error) {
+	header := unix.DmIoctl{
+		Version: [3]uint32{unix.DM_VERSION_MAJOR, dmVersionMinor, dmVersionPatchLevel},
+		//nolint:gosec // Buffer size is bounded by maximumBufferSize
+		Data_size:  uint32(size),
+		Data_start: unix.SizeofDmIoctl,
+		Flags:      flags,
+	}
+
+	copy(header.Name[:unix.DM_NAME_LEN-1], name)
+
+	var request bytes.Buffer
+
+	err := binary.Write(&request, binary.NativeEndian, &header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode device-mapper ioctl: %w", err)
+	}
+
+	buf := make([]byte, size)
+	copy(buf, request.Bytes())
+
+	_, _, errno := unix.Syscall(
+		unix.SYS_IOCTL,
+		control.Fd(),
+		unix.DM_TABLE_STATUS,
+		//nolint:gosec // The kernel writes at most Data_size bytes into buf
+		uintptr(unsafe.Pointer(&buf[0])),
+	)
+	if errno != 0 {
+		return nil, nil, fmt.Errorf("device-mapper ioctl for %s failed: %w", name, errno)
+	}
+
+	var response unix.DmIoctl
+
+	err = binary.Read(bytes.NewReader(buf[:unix.SizeofDmIoctl]), binary.NativeEndian, &response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode device-mapper ioctl: %w", err)
+	}
+
+	return &response, buf, nil
+}
+
+func parseTargets(header *unix.DmIoctl, buf []byte) ([]Target, error) {
+	targets := make([]Target, 0, header.Target_count)
+	offset := int(header.Data_start)
+
+	for range header.Target_count {
+		if offset+unix.SizeofDmTargetSpec > len(buf) {
+			return nil, fmt.Errorf("%w: target spec out of bounds", ErrInvalidResponse)
+		}
+
+		var spec unix.DmTargetSpec
+
+		err := binary.Read(bytes.NewReader(buf[offset:offset+unix.SizeofDmTargetSpec]), binary.NativeEndian, &spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode device-mapper target: %w", err)
+		}
+
+		params := buf[offset+unix.SizeofDmTargetSpec:]
+		if end := bytes.IndexByte(params, 0); end >= 0 {
+			params = params[:end]
+		}
+
+		targets = append(targets, Target{
+			Start:  spec.Sector_start,
+			Length: spec.Length,
+			Type:   utils.CString(spec.Target_type[:]),
+			Params: string(params),
+		})
+
+		// The next offset is relative to the start of the first target spec.
+		offset = int(header.Data_start) + int(spec.Next)
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,9 @@
+// Package devicemapper provides error definitions for device-mapper operations.
+package devicemapper
+
+import "errors"
+
+var (
+	// ErrInvalidResponse is returned when the device-mapper ioctl response cannot be parsed.
+	ErrInvalidResponse = errors.New("invalid device-mapper ioctl response")
+)
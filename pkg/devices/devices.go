@@ -0,0 +1,104 @@
+// Package devices provides utilities to attest the PCI and USB devices attached to the machine.
+package devices
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// State represents the PCI and USB device inventory of the machine.
+type State struct {
+	PCI []PCIDevice `yaml:"pci"`
+	USB []USBDevice `yaml:"usb"`
+}
+
+// Attestable implements the report.Attestable interface for the PCI and USB device inventory.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "devices"
+}
+
+// Measure returns the measurement of the stable part of the device inventory: the identity, class and driver
+// of each device. Runtime state such as bus mastering is only reported as evidence.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetDeviceInventory()
+	if err != nil {
+		return "", fmt.Errorf("failed to get device inventory: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal device inventory: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about each PCI and USB device.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"pci_count": strconv.Itoa(len(a.state.PCI)),
+		"usb_count": strconv.Itoa(len(a.state.USB)),
+		"timestamp": a.timestamp,
+	}
+
+	busMasters := 0
+
+	for _, device := range a.state.PCI {
+		prefix := "pci_" + device.Address + "_"
+		evidence[prefix+"id"] = device.Vendor + ":" + device.Device
+		evidence[prefix+"subsystem_id"] = device.SubsystemVendor + ":" + device.SubsystemDevice
+		evidence[prefix+"class"] = device.Class
+		evidence[prefix+"driver"] = device.Driver
+		evidence[prefix+"bus_master"] = strconv.FormatBool(device.BusMaster)
+
+		if device.BusMaster {
+			busMasters++
+		}
+	}
+
+	for _, device := range a.state.USB {
+		prefix := "usb_" + device.Port + "_"
+		evidence[prefix+"id"] = device.Vendor + ":" + device.Product
+		evidence[prefix+"class"] = device.Class
+		evidence[prefix+"manufacturer"] = device.Manufacturer
+		evidence[prefix+"product"] = device.ProductName
+		evidence[prefix+"serial"] = device.Serial
+		evidence[prefix+"host_controller"] = device.HostController
+		evidence[prefix+"interfaces"] = strings.Join(device.Interfaces, ",")
+	}
+
+	evidence["pci_bus_master_count"] = strconv.Itoa(busMasters)
+
+	return evidence, nil
+}
+
+// GetDeviceInventory walks the PCI and USB buses.
+func GetDeviceInventory() (*State, error) {
+	pci, err := GetPCIDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	usb, err := GetUSBDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		PCI: pci,
+		USB: usb,
+	}, nil
+}
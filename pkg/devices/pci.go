@@ -0,0 +1,95 @@
+package devices
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	pciDevicesPath      = "/sys/bus/pci/devices"
+	pciCommandOffset    = 4
+	pciCommandSize      = 2
+	pciCommandBusMaster = 0x4
+)
+
+// PCIDevice represents a device on the PCI bus.
+type PCIDevice struct {
+	Address         string `yaml:"address"`
+	Vendor          string `yaml:"vendor"`
+	Device          string `yaml:"device"`
+	SubsystemVendor string `yaml:"subsystemVendor"`
+	SubsystemDevice string `yaml:"subsystemDevice"`
+	Class           string `yaml:"class"`
+	Revision        string `yaml:"revision"`
+	Driver          string `yaml:"driver"`
+	BusMaster       bool   `yaml:"-"`
+}
+
+// GetPCIDevices returns the PCI devices sorted by address, with their driver and whether bus mastering,
+// and with it DMA, is enabled.
+func GetPCIDevices() ([]PCIDevice, error) {
+	entries, err := os.ReadDir(pciDevicesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []PCIDevice{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pciDevicesPath, err)
+	}
+
+	devices := make([]PCIDevice, 0, len(entries))
+
+	for _, entry := range entries {
+		dir := filepath.Join(pciDevicesPath, entry.Name())
+
+		devices = append(devices, PCIDevice{
+			Address:         entry.Name(),
+			Vendor:          readID(filepath.Join(dir, "vendor")),
+			Device:          readID(filepath.Join(dir, "device")),
+			SubsystemVendor: readID(filepath.Join(dir, "subsystem_vendor")),
+			SubsystemDevice: readID(filepath.Join(dir, "subsystem_device")),
+			Class:           readID(filepath.Join(dir, "class")),
+			Revision:        readID(filepath.Join(dir, "revision")),
+			Driver:          utils.GetDriver(dir),
+			BusMaster:       isBusMaster(filepath.Join(dir, "config")),
+		})
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Address < devices[j].Address
+	})
+
+	return devices, nil
+}
+
+// isBusMaster reports whether the bus master bit is set in the command register of the PCI configuration space.
+func isBusMaster(configPath string) bool {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	command := make([]byte, pciCommandSize)
+
+	_, err = file.ReadAt(command, pciCommandOffset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+
+	return binary.LittleEndian.Uint16(command)&pciCommandBusMaster != 0
+}
+
+// readID reads a sysfs ID attribute, dropping the "0x" prefix of PCI IDs.
+func readID(path string) string {
+	return strings.TrimPrefix(utils.ReadSysfsValue(path), "0x")
+}
@@ -0,0 +1,116 @@
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	usbDevicesPath     = "/sys/bus/usb/devices"
+	usbInterfaceMarker = ":"
+	usbRootHubPrefix   = "usb"
+	usbRootHubPort     = "-0"
+)
+
+// USBDevice represents a device on the USB bus with the class and driver of each of its interfaces.
+// The strings of root hubs are generated by the kernel and embed its release, e.g. "Linux 6.6.0 xhci-hcd",
+// so they are kept in HostController instead of Manufacturer and ProductName.
+type USBDevice struct {
+	Port           string   `yaml:"-"`
+	Vendor         string   `yaml:"vendor"`
+	Product        string   `yaml:"product"`
+	Class          string   `yaml:"class"`
+	Manufacturer   string   `yaml:"manufacturer"`
+	ProductName    string   `yaml:"productName"`
+	HostController string   `yaml:"-"`
+	Serial         string   `yaml:"-"`
+	Interfaces     []string `yaml:"interfaces"`
+}
+
+// GetUSBDevices returns the USB devices, including root hubs, sorted by vendor, product and port.
+// Interfaces are reported as "<class>:<driver>".
+func GetUSBDevices() ([]USBDevice, error) {
+	entries, err := os.ReadDir(usbDevicesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []USBDevice{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", usbDevicesPath, err)
+	}
+
+	devices := make(map[string]*USBDevice)
+	interfaces := make(map[string][]string)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		dir := filepath.Join(usbDevicesPath, name)
+
+		// Interfaces are named "<port>:<configuration>.<interface>", e.g. "1-1:1.0".
+		// Root hub interfaces are named "<bus>-0:1.0" while the root hub itself is named "usb<bus>".
+		port, _, isInterface := strings.Cut(name, usbInterfaceMarker)
+		if isInterface {
+			if bus, isRootHub := strings.CutSuffix(port, usbRootHubPort); isRootHub {
+				port = usbRootHubPrefix + bus
+			}
+
+			interfaces[port] = append(interfaces[port],
+				utils.ReadSysfsValue(filepath.Join(dir, "bInterfaceClass"))+":"+utils.GetDriver(dir))
+
+			continue
+		}
+
+		vendor := utils.ReadSysfsValue(filepath.Join(dir, "idVendor"))
+		if vendor == "" {
+			continue
+		}
+
+		device := &USBDevice{
+			Port:         name,
+			Vendor:       vendor,
+			Product:      utils.ReadSysfsValue(filepath.Join(dir, "idProduct")),
+			Class:        utils.ReadSysfsValue(filepath.Join(dir, "bDeviceClass")),
+			Manufacturer: utils.ReadSysfsValue(filepath.Join(dir, "manufacturer")),
+			ProductName:  utils.ReadSysfsValue(filepath.Join(dir, "product")),
+			Serial:       utils.ReadSysfsValue(filepath.Join(dir, "serial")),
+		}
+
+		if strings.HasPrefix(name, usbRootHubPrefix) {
+			device.HostController = strings.TrimSpace(device.Manufacturer + " " + device.ProductName)
+			device.Manufacturer = ""
+			device.ProductName = ""
+		}
+
+		devices[name] = device
+	}
+
+	result := make([]USBDevice, 0, len(devices))
+
+	for port, device := range devices {
+		device.Interfaces = interfaces[port]
+		if device.Interfaces == nil {
+			device.Interfaces = []string{}
+		}
+
+		sort.Strings(device.Interfaces)
+		result = append(result, *device)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Vendor != result[j].Vendor {
+			return result[i].Vendor < result[j].Vendor
+		}
+
+		if result[i].Product != result[j].Product {
+			return result[i].Product < result[j].Product
+		}
+
+		return result[i].Port < result[j].Port
+	})
+
+	return result, nil
+}
@@ -0,0 +1,230 @@
+package ebpf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	fdInfoPath         = "/proc/self/fdinfo"
+	fdInfoProgTag      = "prog_tag"
+	fdInfoMapType      = "map_type"
+	fdInfoLinkType     = "link_type"
+	objectNameSize     = 16
+	objectTagSize      = 8
+	objectKindProgram  = "program"
+	objectKindMap      = "map"
+	objectKindLink     = "link"
+	objectKindUnknown  = ""
+	fdInfoKeyValueSize = 2
+)
+
+// idAttr mirrors the BPF_*_GET_NEXT_ID and BPF_*_GET_FD_BY_ID members of union bpf_attr.
+type idAttr struct {
+	ID        uint32
+	NextID    uint32
+	OpenFlags uint32
+}
+
+// objGetAttr mirrors the BPF_OBJ_GET members of union bpf_attr.
+type objGetAttr struct {
+	Pathname  uint64
+	BpfFd     uint32
+	FileFlags uint32
+}
+
+// infoAttr mirrors the BPF_OBJ_GET_INFO_BY_FD members of union bpf_attr.
+type infoAttr struct {
+	BpfFd   uint32
+	InfoLen uint32
+	Info    uint64
+}
+
+// progInfo mirrors the leading members of struct bpf_prog_info.
+type progInfo struct {
+	Type            uint32
+	ID              uint32
+	Tag             [objectTagSize]byte
+	JitedProgLen    uint32
+	XlatedProgLen   uint32
+	JitedProgInsns  uint64
+	XlatedProgInsns uint64
+	LoadTime        uint64
+	CreatedByUID    uint32
+	NrMapIDs        uint32
+	MapIDs          uint64
+	Name            [objectNameSize]byte
+}
+
+// mapInfo mirrors the leading members of struct bpf_map_info.
+type mapInfo struct {
+	Type       uint32
+	ID         uint32
+	KeySize    uint32
+	ValueSize  uint32
+	MaxEntries uint32
+	MapFlags   uint32
+	Name       [objectNameSize]byte
+}
+
+// linkInfo mirrors the leading members of struct bpf_link_info.
+type linkInfo struct {
+	Type   uint32
+	ID     uint32
+	ProgID uint32
+	_      uint32
+}
+
+func bpf(cmd int, attr unsafe.Pointer, size uintptr) (int, error) {
+	fd, _, errno := unix.Syscall(unix.SYS_BPF, uintptr(cmd), uintptr(attr), size)
+	if errno != 0 {
+		return -1, errno
+	}
+
+	return int(fd), nil
+}
+
+// forEachID calls fn with a file descriptor for each object enumerated by the given BPF_*_GET_NEXT_ID command.
+// Objects that are unloaded while being enumerated are skipped.
+func forEachID(nextCmd int, fdCmd int, fn func(fd int) error) error {
+	var id uint32
+
+	for {
+		attr := idAttr{ID: id}
+
+		_, err := bpf(nextCmd, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+		if errors.Is(err, unix.ENOENT) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get next BPF object ID after %d: %w", id, err)
+		}
+
+		id = attr.NextID
+
+		fdAttr := idAttr{ID: id}
+
+		fd, err := bpf(fdCmd, unsafe.Pointer(&fdAttr), unsafe.Sizeof(fdAttr))
+		if errors.Is(err, unix.ENOENT) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get BPF object %d: %w", id, err)
+		}
+
+		err = fn(fd)
+
+		_ = unix.Close(fd)
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// objGet opens the BPF object pinned at path in bpffs.
+func objGet(path string) (int, error) {
+	pathname, err := unix.BytePtrFromString(path)
+	if err != nil {
+		return -1, fmt.Errorf("invalid bpffs path %s: %w", path, err)
+	}
+
+	attr := objGetAttr{
+		//nolint:gosec // The pathname is kept alive until the syscall returns
+		Pathname: uint64(uintptr(unsafe.Pointer(pathname))),
+	}
+
+	fd, err := bpf(unix.BPF_OBJ_GET, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+
+	runtime.KeepAlive(pathname)
+
+	if err != nil {
+		return -1, fmt.Errorf("failed to open pinned BPF object %s: %w", path, err)
+	}
+
+	return fd, nil
+}
+
+// objInfo fills info with the kernel information about the BPF object referred to by fd.
+func objInfo(fd int, info unsafe.Pointer, size uintptr) error {
+	attr := infoAttr{
+		//nolint:gosec // File descriptors are non-negative
+		BpfFd: uint32(fd),
+		//nolint:gosec // Info structures are small
+		InfoLen: uint32(size),
+		Info:    uint64(uintptr(info)),
+	}
+
+	_, err := bpf(unix.BPF_OBJ_GET_INFO_BY_FD, unsafe.Pointer(&attr), unsafe.Sizeof(attr))
+	if err != nil {
+		return fmt.Errorf("failed to get BPF object info: %w", err)
+	}
+
+	return nil
+}
+
+func getProgInfo(fd int) (*progInfo, error) {
+	info := &progInfo{}
+
+	err := objInfo(fd, unsafe.Pointer(info), unsafe.Sizeof(*info))
+	runtime.KeepAlive(info)
+
+	return info, err
+}
+
+func getMapInfo(fd int) (*mapInfo, error) {
+	info := &mapInfo{}
+
+	err := objInfo(fd, unsafe.Pointer(info), unsafe.Sizeof(*info))
+	runtime.KeepAlive(info)
+
+	return info, err
+}
+
+func getLinkInfo(fd int) (*linkInfo, error) {
+	info := &linkInfo{}
+
+	err := objInfo(fd, unsafe.Pointer(info), unsafe.Sizeof(*info))
+	runtime.KeepAlive(info)
+
+	return info, err
+}
+
+// objectKind returns whether fd refers to a BPF program, map or link based on its fdinfo.
+func objectKind(fd int) string {
+	file, err := os.Open(fdInfoPath + "/" + strconv.Itoa(fd))
+	if err != nil {
+		return objectKindUnknown
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	keys := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", fdInfoKeyValueSize)
+		if len(parts) == fdInfoKeyValueSize {
+			keys[parts[0]] = true
+		}
+	}
+
+	switch {
+	case keys[fdInfoLinkType]:
+		return objectKindLink
+	case keys[fdInfoProgTag]:
+		return objectKindProgram
+	case keys[fdInfoMapType]:
+		return objectKindMap
+	default:
+		return objectKindUnknown
+	}
+}
@@ -0,0 +1,371 @@
+// Package ebpf provides utilities to attest the eBPF programs and maps loaded into the kernel.
+package ebpf
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	bpffsPath = "/sys/fs/bpf"
+
+	// SourceSyscall is reported when the objects were enumerated through the bpf(2) ID interfaces.
+	SourceSyscall = "syscall"
+	// SourceBPFFS is reported when the objects were enumerated from the objects pinned in bpffs.
+	SourceBPFFS = "bpffs"
+)
+
+//nolint:gochecknoglobals
+var programTypes = []string{
+	"unspec", "socket_filter", "kprobe", "sched_cls", "sched_act", "tracepoint", "xdp", "perf_event",
+	"cgroup_skb", "cgroup_sock", "lwt_in", "lwt_out", "lwt_xmit", "sock_ops", "sk_skb", "cgroup_device",
+	"sk_msg", "raw_tracepoint", "cgroup_sock_addr", "lwt_seg6local", "lirc_mode2", "sk_reuseport",
+	"flow_dissector", "cgroup_sysctl", "raw_tracepoint_writable", "cgroup_sockopt", "tracing", "struct_ops",
+	"ext", "lsm", "sk_lookup", "syscall", "netfilter",
+}
+
+//nolint:gochecknoglobals
+var mapTypes = []string{
+	"unspec", "hash", "array", "prog_array", "perf_event_array", "percpu_hash", "percpu_array", "stack_trace",
+	"cgroup_array", "lru_hash", "lru_percpu_hash", "lpm_trie", "array_of_maps", "hash_of_maps", "devmap",
+	"sockmap", "cpumap", "xskmap", "sockhash", "cgroup_storage", "reuseport_sockarray", "percpu_cgroup_storage",
+	"queue", "stack", "sk_storage", "devmap_hash", "struct_ops", "ringbuf", "inode_storage", "task_storage",
+	"bloom_filter", "user_ringbuf", "cgrp_storage", "arena",
+}
+
+//nolint:gochecknoglobals
+var linkTypes = []string{
+	"unspec", "raw_tracepoint", "tracing", "cgroup", "iter", "netns", "xdp", "perf_event", "kprobe_multi",
+	"struct_ops", "netfilter", "tcx", "uprobe_multi", "netkit", "sockmap",
+}
+
+// Program represents a loaded eBPF program. AttachPoints only lists the types of the BPF links that reference
+// the program: legacy attachments without a link, such as tc filters or cgroup programs attached with
+// BPF_PROG_ATTACH, are not reported.
+type Program struct {
+	ID           uint32   `yaml:"id"`
+	Type         string   `yaml:"type"`
+	Name         string   `yaml:"name"`
+	Tag          string   `yaml:"tag"`
+	LoadTime     string   `yaml:"loadTime"`
+	UID          uint32   `yaml:"uid"`
+	AttachPoints []string `yaml:"attachPoints"`
+}
+
+// Map represents a loaded eBPF map.
+type Map struct {
+	ID         uint32 `yaml:"id"`
+	Type       string `yaml:"type"`
+	Name       string `yaml:"name"`
+	KeySize    uint32 `yaml:"keySize"`
+	ValueSize  uint32 `yaml:"valueSize"`
+	MaxEntries uint32 `yaml:"maxEntries"`
+}
+
+// State represents the eBPF programs and maps loaded into the kernel.
+type State struct {
+	Source   string    `yaml:"source"`
+	Programs []Program `yaml:"programs"`
+	Maps     []Map     `yaml:"maps"`
+}
+
+// Attestable implements the report.Attestable interface for the loaded eBPF programs and maps.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "ebpf"
+}
+
+// Measure returns the measurement of the loaded eBPF program tags and the number of programs loaded with each tag.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetEBPFState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get eBPF state: %w", err)
+	}
+
+	data, err := yaml.Marshal(programTagCounts(state.Programs))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal eBPF program tags: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the loaded eBPF programs and maps.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"source":         a.state.Source,
+		"programs_count": strconv.Itoa(len(a.state.Programs)),
+		"maps_count":     strconv.Itoa(len(a.state.Maps)),
+		"timestamp":      a.timestamp,
+	}
+
+	uids := make([]string, 0)
+
+	for i, program := range a.state.Programs {
+		prefix := fmt.Sprintf("program_%d_", i)
+		uid := strconv.FormatUint(uint64(program.UID), 10)
+
+		evidence[prefix+"id"] = strconv.FormatUint(uint64(program.ID), 10)
+		evidence[prefix+"type"] = program.Type
+		evidence[prefix+"name"] = program.Name
+		evidence[prefix+"tag"] = program.Tag
+		evidence[prefix+"load_time"] = program.LoadTime
+		evidence[prefix+"uid"] = uid
+		evidence[prefix+"attach_points"] = strings.Join(program.AttachPoints, ",")
+
+		if !slices.Contains(uids, uid) {
+			uids = append(uids, uid)
+		}
+	}
+
+	for i, m := range a.state.Maps {
+		prefix := fmt.Sprintf("map_%d_", i)
+		evidence[prefix+"id"] = strconv.FormatUint(uint64(m.ID), 10)
+		evidence[prefix+"type"] = m.Type
+		evidence[prefix+"name"] = m.Name
+	}
+
+	sort.Strings(uids)
+	evidence["owner_uids"] = strings.Join(uids, ",")
+
+	return evidence, nil
+}
+
+// GetEBPFState enumerates the loaded eBPF programs, maps and links through the bpf(2) ID interfaces.
+// When enumeration is not permitted, it falls back to the objects pinned in bpffs.
+func GetEBPFState() (*State, error) {
+	collector := newCollector()
+
+	err := collector.collectByID()
+	if errors.Is(err, unix.EPERM) || errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EINVAL) {
+		collector = newCollector()
+
+		err = collector.collectPinned()
+		if err != nil {
+			return nil, err
+		}
+
+		return collector.state(SourceBPFFS), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return collector.state(SourceSyscall), nil
+}
+
+type collector struct {
+	bootTime     time.Time
+	programs     map[uint32]Program
+	maps         map[uint32]Map
+	attachPoints map[uint32][]string
+}
+
+// newCollector returns an empty collector. Program load times are relative to boot and are converted
+// with the boot time, which falls back to the epoch when it cannot be read.
+func newCollector() *collector {
+	bootTime, err := utils.BootTime()
+	if err != nil {
+		bootTime = time.Unix(0, 0)
+	}
+
+	return &collector{
+		bootTime:     bootTime,
+		programs:     make(map[uint32]Program),
+		maps:         make(map[uint32]Map),
+		attachPoints: make(map[uint32][]string),
+	}
+}
+
+func (c *collector) collectByID() error {
+	err := forEachID(unix.BPF_PROG_GET_NEXT_ID, unix.BPF_PROG_GET_FD_BY_ID, c.addProgram)
+	if err != nil {
+		return fmt.Errorf("failed to enumerate BPF programs: %w", err)
+	}
+
+	err = forEachID(unix.BPF_MAP_GET_NEXT_ID, unix.BPF_MAP_GET_FD_BY_ID, c.addMap)
+	if err != nil {
+		return fmt.Errorf("failed to enumerate BPF maps: %w", err)
+	}
+
+	err = forEachID(unix.BPF_LINK_GET_NEXT_ID, unix.BPF_LINK_GET_FD_BY_ID, c.addLink)
+	if err != nil && !errors.Is(err, unix.EINVAL) {
+		return fmt.Errorf("failed to enumerate BPF links: %w", err)
+	}
+
+	return nil
+}
+
+func (c *collector) collectPinned() error {
+	err := filepath.WalkDir(bpffsPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil //nolint:nilerr // Unreadable bpffs directories are skipped
+		}
+
+		fd, err := objGet(path)
+		if err != nil {
+			return nil //nolint:nilerr // Objects that cannot be opened are skipped
+		}
+
+		defer func() {
+			_ = unix.Close(fd)
+		}()
+
+		switch objectKind(fd) {
+		case objectKindProgram:
+			return c.addProgram(fd)
+		case objectKindMap:
+			return c.addMap(fd)
+		case objectKindLink:
+			return c.addLink(fd)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to walk %s: %w", bpffsPath, err)
+	}
+
+	return nil
+}
+
+func (c *collector) addProgram(fd int) error {
+	info, err := getProgInfo(fd)
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // Load times since boot fit in int64
+	loadTime := c.bootTime.Add(time.Duration(info.LoadTime))
+
+	c.programs[info.ID] = Program{
+		ID:       info.ID,
+		Type:     typeName(programTypes, info.Type),
+		Name:     utils.CString(info.Name[:]),
+		Tag:      hex.EncodeToString(info.Tag[:]),
+		LoadTime: strconv.FormatInt(loadTime.Unix(), 10),
+		UID:      info.CreatedByUID,
+	}
+
+	return nil
+}
+
+func (c *collector) addMap(fd int) error {
+	info, err := getMapInfo(fd)
+	if err != nil {
+		return err
+	}
+
+	c.maps[info.ID] = Map{
+		ID:         info.ID,
+		Type:       typeName(mapTypes, info.Type),
+		Name:       utils.CString(info.Name[:]),
+		KeySize:    info.KeySize,
+		ValueSize:  info.ValueSize,
+		MaxEntries: info.MaxEntries,
+	}
+
+	return nil
+}
+
+// addLink records the type of the link as an attach point of the program it references.
+// Only BPF links are visible here: programs attached through the legacy netlink or BPF_PROG_ATTACH
+// interfaces have no link and therefore no attach point.
+func (c *collector) addLink(fd int) error {
+	info, err := getLinkInfo(fd)
+	if err != nil {
+		return err
+	}
+
+	attachPoint := typeName(linkTypes, info.Type)
+	if !slices.Contains(c.attachPoints[info.ProgID], attachPoint) {
+		c.attachPoints[info.ProgID] = append(c.attachPoints[info.ProgID], attachPoint)
+	}
+
+	return nil
+}
+
+// state returns the collected programs sorted by tag and the collected maps sorted by type and name.
+func (c *collector) state(source string) *State {
+	state := &State{
+		Source:   source,
+		Programs: make([]Program, 0, len(c.programs)),
+		Maps:     make([]Map, 0, len(c.maps)),
+	}
+
+	for id, program := range c.programs {
+		program.AttachPoints = c.attachPoints[id]
+		if program.AttachPoints == nil {
+			program.AttachPoints = []string{}
+		}
+
+		sort.Strings(program.AttachPoints)
+		state.Programs = append(state.Programs, program)
+	}
+
+	for _, m := range c.maps {
+		state.Maps = append(state.Maps, m)
+	}
+
+	sort.Slice(state.Programs, func(i, j int) bool {
+		if state.Programs[i].Tag != state.Programs[j].Tag {
+			return state.Programs[i].Tag < state.Programs[j].Tag
+		}
+
+		return state.Programs[i].ID < state.Programs[j].ID
+	})
+
+	sort.Slice(state.Maps, func(i, j int) bool {
+		if state.Maps[i].Type != state.Maps[j].Type {
+			return state.Maps[i].Type < state.Maps[j].Type
+		}
+
+		if state.Maps[i].Name != state.Maps[j].Name {
+			return state.Maps[i].Name < state.Maps[j].Name
+		}
+
+		return state.Maps[i].ID < state.Maps[j].ID
+	})
+
+	return state
+}
+
+// programTagCounts returns the number of programs loaded with each tag, so that a second instance of the
+// same program changes the measurement.
+func programTagCounts(programs []Program) map[string]int {
+	counts := make(map[string]int, len(programs))
+
+	for _, program := range programs {
+		counts[program.Tag]++
+	}
+
+	return counts
+}
+
+func typeName(names []string, value uint32) string {
+	if int(value) < len(names) {
+		return names[value]
+	}
+
+	return strconv.FormatUint(uint64(value), 10)
+}
@@ -0,0 +1,317 @@
+// Package encryption provides utilities to attest the disk encryption posture of the Talos partitions.
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/devicemapper"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	sysClassBlockDir  = "/sys/class/block"
+	cryptUUIDPrefix   = "CRYPT-"
+	cryptTargetType   = "crypt"
+	keyringKeyPrefix  = ":"
+	keyringKeyFields  = 4
+	cryptMinParams    = 2
+	hexCharsPerByte   = 2
+	partNameUeventKey = "PARTNAME="
+)
+
+// talosPartitionLabels lists the Talos partition labels whose encryption posture is reported.
+//
+//nolint:gochecknoglobals
+var talosPartitionLabels = []string{"STATE", "EPHEMERAL"}
+
+// State represents the disk encryption posture of the machine.
+type State struct {
+	Partitions []Partition    `yaml:"partitions"`
+	Mappings   []CryptMapping `yaml:"mappings"`
+}
+
+// Partition represents the encryption posture of a Talos partition.
+type Partition struct {
+	Label     string      `yaml:"label"`
+	Device    string      `yaml:"device"`
+	Encrypted bool        `yaml:"encrypted"`
+	Mapping   string      `yaml:"mapping"`
+	Cipher    string      `yaml:"cipher"`
+	KeySize   int         `yaml:"keySize"` // bits
+	LUKS      *LUKSHeader `yaml:"luks,omitempty"`
+	StaticKey bool        `yaml:"staticKey"`
+}
+
+// CryptMapping represents an active dm-crypt mapping, without any key material. The dm-N device follows the
+// order in which mappings are created and is only reported as evidence.
+type CryptMapping struct {
+	Name    string `yaml:"name"`
+	Device  string `yaml:"-"`
+	UUID    string `yaml:"uuid"`
+	Cipher  string `yaml:"cipher"`
+	KeySize int    `yaml:"keySize"` // bits
+}
+
+// Attestable implements the report.Attestable interface for disk encryption.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "disk-encryption"
+}
+
+// Measure returns the measurement of the disk encryption posture.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetEncryptionState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get disk encryption state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal disk encryption state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the disk encryption posture.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"partitions_count": strconv.Itoa(len(a.state.Partitions)),
+		"mappings_count":   strconv.Itoa(len(a.state.Mappings)),
+		"timestamp":        a.timestamp,
+	}
+
+	for i, partition := range a.state.Partitions {
+		prefix := fmt.Sprintf("partition_%d_", i)
+		evidence[prefix+"label"] = partition.Label
+		evidence[prefix+"device"] = partition.Device
+		evidence[prefix+"encrypted"] = strconv.FormatBool(partition.Encrypted)
+		evidence[prefix+"mapping"] = partition.Mapping
+		evidence[prefix+"cipher"] = partition.Cipher
+		evidence[prefix+"key_size"] = strconv.Itoa(partition.KeySize)
+		evidence[prefix+"static_key"] = strconv.FormatBool(partition.StaticKey)
+
+		if partition.LUKS == nil {
+			continue
+		}
+
+		evidence[prefix+"luks_version"] = strconv.Itoa(int(partition.LUKS.Version))
+		evidence[prefix+"luks_uuid"] = partition.LUKS.UUID
+		evidence[prefix+"luks_label"] = partition.LUKS.Label
+		evidence[prefix+"luks_sector_size"] = strconv.Itoa(partition.LUKS.SectorSize)
+		evidence[prefix+"keyslots_count"] = strconv.Itoa(len(partition.LUKS.Keyslots))
+
+		for j, keyslot := range partition.LUKS.Keyslots {
+			keyslotPrefix := fmt.Sprintf("%skeyslot_%d_", prefix, j)
+			evidence[keyslotPrefix+"id"] = keyslot.ID
+			evidence[keyslotPrefix+"key_size"] = strconv.Itoa(keyslot.KeySize)
+			evidence[keyslotPrefix+"kdf"] = keyslot.KDF
+			evidence[keyslotPrefix+"provider"] = keyslot.Provider
+		}
+	}
+
+	for i, mapping := range a.state.Mappings {
+		prefix := fmt.Sprintf("mapping_%d_", i)
+		evidence[prefix+"name"] = mapping.Name
+		evidence[prefix+"device"] = mapping.Device
+		evidence[prefix+"uuid"] = mapping.UUID
+		evidence[prefix+"cipher"] = mapping.Cipher
+		evidence[prefix+"key_size"] = strconv.Itoa(mapping.KeySize)
+	}
+
+	return evidence, nil
+}
+
+// GetEncryptionState lists the dm-crypt mappings and reports the encryption posture of the Talos partitions.
+func GetEncryptionState() (*State, error) {
+	mappings, err := getCryptMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(sysClassBlockDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{Partitions: []Partition{}, Mappings: mappings}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sysClassBlockDir, err)
+	}
+
+	partitions := make([]Partition, 0, len(talosPartitionLabels))
+
+	for _, label := range talosPartitionLabels {
+		device := findPartition(entries, label)
+		if device == "" {
+			continue
+		}
+
+		partition, err := getPartition(label, device, mappings)
+		if err != nil {
+			return nil, err
+		}
+
+		partitions = append(partitions, *partition)
+	}
+
+	return &State{
+		Partitions: partitions,
+		Mappings:   mappings,
+	}, nil
+}
+
+func getCryptMappings() ([]CryptMapping, error) {
+	devices, err := devicemapper.ListDevices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device-mapper devices: %w", err)
+	}
+
+	mappings := make([]CryptMapping, 0)
+
+	for _, device := range devices {
+		if !strings.HasPrefix(device.UUID, cryptUUIDPrefix) {
+			continue
+		}
+
+		table, err := devicemapper.Table(device.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table of %s: %w", device.Name, err)
+		}
+
+		mapping := CryptMapping{
+			Name:   device.Name,
+			Device: device.Kernel,
+			UUID:   device.UUID,
+		}
+
+		for _, target := range table {
+			params := strings.Fields(target.Params)
+			if target.Type != cryptTargetType || len(params) < cryptMinParams {
+				continue
+			}
+
+			mapping.Cipher = params[0]
+			mapping.KeySize = keySize(params[1])
+
+			break
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+func findPartition(entries []os.DirEntry, label string) string {
+	for _, entry := range entries {
+		dir := filepath.Join(sysClassBlockDir, entry.Name())
+
+		_, err := os.Stat(filepath.Join(dir, "partition"))
+		if err != nil {
+			continue
+		}
+
+		//nolint:gosec // Sysfs path is controlled
+		uevent, err := os.ReadFile(filepath.Join(dir, "uevent"))
+		if err != nil {
+			continue
+		}
+
+		for line := range strings.SplitSeq(string(uevent), "\n") {
+			if name, ok := strings.CutPrefix(line, partNameUeventKey); ok && name == label {
+				return entry.Name()
+			}
+		}
+	}
+
+	return ""
+}
+
+func getPartition(label string, device string, mappings []CryptMapping) (*Partition, error) {
+	partition := &Partition{
+		Label:  label,
+		Device: device,
+	}
+
+	holders, err := os.ReadDir(filepath.Join(sysClassBlockDir, device, "holders"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read holders of %s: %w", device, err)
+	}
+
+	for _, holder := range holders {
+		for _, mapping := range mappings {
+			if mapping.Device != holder.Name() {
+				continue
+			}
+
+			partition.Encrypted = true
+			partition.Mapping = mapping.Name
+			partition.Cipher = mapping.Cipher
+			partition.KeySize = mapping.KeySize
+		}
+	}
+
+	header, err := readLUKSHeader(filepath.Join("/dev", device))
+	if err != nil {
+		return nil, err
+	}
+
+	if header == nil {
+		return partition, nil
+	}
+
+	partition.Encrypted = true
+	partition.LUKS = header
+
+	if partition.Cipher == "" {
+		partition.Cipher = header.Cipher
+	}
+
+	for _, keyslot := range header.Keyslots {
+		if partition.KeySize == 0 {
+			partition.KeySize = keyslot.KeySize
+		}
+
+		if keyslot.Provider == ProviderStatic {
+			partition.StaticKey = true
+		}
+	}
+
+	return partition, nil
+}
+
+// keySize returns the size in bits of a dm-crypt key, either given as a keyring reference
+// (:<size>:<type>:<description>) or as hex. The key itself is never retained.
+func keySize(key string) int {
+	if strings.HasPrefix(key, keyringKeyPrefix) {
+		fields := strings.SplitN(key, ":", keyringKeyFields)
+		if len(fields) < keyringKeyFields {
+			return 0
+		}
+
+		size, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0
+		}
+
+		return size * bitsPerByte
+	}
+
+	if key == "-" {
+		return 0
+	}
+
+	return len(key) / hexCharsPerByte * bitsPerByte
+}
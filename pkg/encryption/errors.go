@@ -0,0 +1,9 @@
+// Package encryption provides error definitions for disk encryption operations.
+package encryption
+
+import "errors"
+
+var (
+	// ErrInvalidLUKSHeader is returned when the LUKS2 header of a partition cannot be parsed.
+	ErrInvalidLUKSHeader = errors.New("invalid LUKS2 header")
+)
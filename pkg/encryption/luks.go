@@ -0,0 +1,179 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	luksMagic            = "LUKS\xba\xbe"
+	luksBinaryHeaderSize = 4096
+	luksMaxHeaderSize    = 4 * 1024 * 1024
+	luksDataSegment      = "0"
+	bitsPerByte          = 8
+
+	// ProviderStatic is reported for key slots without a token, i.e. unlocked by a static key or passphrase.
+	ProviderStatic = "static"
+	// ProviderTPM is reported for key slots unlocked through a TPM sealed token.
+	ProviderTPM = "tpm"
+	// ProviderKMS is reported for key slots unlocked through a KMS token.
+	ProviderKMS = "kms"
+	// ProviderFIDO2 is reported for key slots unlocked through a FIDO2 token.
+	ProviderFIDO2 = "fido2"
+)
+
+// LUKSHeader represents the metadata of a LUKS header, without any key material.
+type LUKSHeader struct {
+	Version    uint16    `yaml:"version"`
+	UUID       string    `yaml:"uuid"`
+	Label      string    `yaml:"label"`
+	Cipher     string    `yaml:"cipher"`
+	SectorSize int       `yaml:"sectorSize"`
+	Keyslots   []Keyslot `yaml:"keyslots"`
+}
+
+// Keyslot represents a LUKS2 key slot and the provider of its key.
+type Keyslot struct {
+	ID       string `yaml:"id"`
+	KeySize  int    `yaml:"keySize"` // bits
+	KDF      string `yaml:"kdf"`
+	Provider string `yaml:"provider"`
+}
+
+type luks2Metadata struct {
+	Keyslots map[string]struct {
+		KeySize int `json:"key_size"`
+		KDF     struct {
+			Type string `json:"type"`
+		} `json:"kdf"`
+	} `json:"keyslots"`
+	Tokens map[string]struct {
+		Type     string   `json:"type"`
+		Keyslots []string `json:"keyslots"`
+	} `json:"tokens"`
+	Segments map[string]struct {
+		Encryption string `json:"encryption"`
+		SectorSize int    `json:"sector_size"`
+	} `json:"segments"`
+}
+
+// readLUKSHeader reads the LUKS header of the given device. It returns nil if the device is not LUKS formatted.
+func readLUKSHeader(devicePath string) (*LUKSHeader, error) {
+	//nolint:gosec // Device path is resolved from sysfs
+	file, err := os.Open(devicePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", devicePath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	binaryHeader := make([]byte, luksBinaryHeaderSize)
+
+	_, err = io.ReadFull(file, binaryHeader)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return nil, nil //nolint:nilnil // Device is too small to hold a LUKS header
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read LUKS header of %s: %w", devicePath, err)
+	}
+
+	if !bytes.HasPrefix(binaryHeader, []byte(luksMagic)) {
+		return nil, nil //nolint:nilnil // Device is not LUKS formatted
+	}
+
+	header := &LUKSHeader{
+		Version:  binary.BigEndian.Uint16(binaryHeader[6:8]),
+		UUID:     utils.CString(binaryHeader[168:208]),
+		Keyslots: make([]Keyslot, 0),
+	}
+
+	if header.Version != 2 { //nolint:mnd // Only LUKS2 carries JSON metadata
+		header.Cipher = utils.CString(binaryHeader[8:40]) + "-" + utils.CString(binaryHeader[40:72])
+
+		return header, nil
+	}
+
+	header.Label = utils.CString(binaryHeader[24:72])
+
+	headerSize := binary.BigEndian.Uint64(binaryHeader[8:16])
+	if headerSize <= luksBinaryHeaderSize || headerSize > luksMaxHeaderSize {
+		return nil, fmt.Errorf("%w: %s: header size %d", ErrInvalidLUKSHeader, devicePath, headerSize)
+	}
+
+	jsonArea := make([]byte, headerSize-luksBinaryHeaderSize)
+
+	_, err = io.ReadFull(file, jsonArea)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LUKS2 metadata of %s: %w", devicePath, err)
+	}
+
+	var metadata luks2Metadata
+
+	err = json.Unmarshal(bytes.TrimRight(jsonArea, "\x00"), &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLUKSHeader, devicePath, err)
+	}
+
+	if segment, ok := metadata.Segments[luksDataSegment]; ok {
+		header.Cipher = segment.Encryption
+		header.SectorSize = segment.SectorSize
+	}
+
+	providers := make(map[string]string)
+
+	for _, token := range metadata.Tokens {
+		for _, keyslot := range token.Keyslots {
+			providers[keyslot] = tokenProvider(token.Type)
+		}
+	}
+
+	for id, keyslot := range metadata.Keyslots {
+		provider, ok := providers[id]
+		if !ok {
+			provider = ProviderStatic
+		}
+
+		header.Keyslots = append(header.Keyslots, Keyslot{
+			ID:       id,
+			KeySize:  keyslot.KeySize * bitsPerByte,
+			KDF:      keyslot.KDF.Type,
+			Provider: provider,
+		})
+	}
+
+	sort.Slice(header.Keyslots, func(i, j int) bool {
+		// Key slot IDs are decimal strings, so shorter IDs sort first.
+		if len(header.Keyslots[i].ID) != len(header.Keyslots[j].ID) {
+			return len(header.Keyslots[i].ID) < len(header.Keyslots[j].ID)
+		}
+
+		return header.Keyslots[i].ID < header.Keyslots[j].ID
+	})
+
+	return header, nil
+}
+
+func tokenProvider(tokenType string) string {
+	lower := strings.ToLower(tokenType)
+
+	switch {
+	case strings.Contains(lower, "tpm"):
+		return ProviderTPM
+	case strings.Contains(lower, "kms"):
+		return ProviderKMS
+	case strings.Contains(lower, "fido"):
+		return ProviderFIDO2
+	default:
+		return tokenType
+	}
+}
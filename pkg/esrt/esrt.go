@@ -0,0 +1,149 @@
+// Package esrt provides utilities to attest the firmware versions from the EFI System Resource Table.
+package esrt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	esrtEntriesDir = "/sys/firmware/efi/esrt/entries"
+)
+
+//nolint:gochecknoglobals
+var firmwareTypes = map[string]string{
+	"0": "unknown",
+	"1": "system-firmware",
+	"2": "device-firmware",
+	"3": "uefi-driver",
+}
+
+//nolint:gochecknoglobals
+var lastAttemptStatuses = map[string]string{
+	"0": "success",
+	"1": "unsuccessful",
+	"2": "insufficient-resources",
+	"3": "incorrect-version",
+	"4": "invalid-format",
+	"5": "auth-error",
+	"6": "power-event-ac",
+	"7": "power-event-battery",
+	"8": "unsatisfied-dependencies",
+}
+
+// Entry represents a single firmware resource entry of the ESRT.
+type Entry struct {
+	Name                   string `yaml:"name"`
+	Class                  string `yaml:"class"`
+	Type                   string `yaml:"type"`
+	Version                uint32 `yaml:"version"`
+	LowestSupportedVersion uint32 `yaml:"lowestSupportedVersion"`
+	LastAttemptVersion     uint32 `yaml:"lastAttemptVersion"`
+	LastAttemptStatus      string `yaml:"lastAttemptStatus"`
+	CapsuleFlags           string `yaml:"capsuleFlags"`
+}
+
+// Attestable implements the report.Attestable interface for the EFI System Resource Table.
+type Attestable struct {
+	entries   []Entry
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "esrt"
+}
+
+// Measure returns the measurement of the firmware resource versions.
+func (a *Attestable) Measure() (string, error) {
+	entries, err := GetEntries()
+	if err != nil {
+		return "", fmt.Errorf("failed to get ESRT entries: %w", err)
+	}
+
+	data, err := yaml.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ESRT entries: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.entries = entries
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the firmware resource versions.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"entries_count": strconv.Itoa(len(a.entries)),
+		"timestamp":     a.timestamp,
+	}
+
+	for i, entry := range a.entries {
+		prefix := fmt.Sprintf("entry_%d_", i)
+		evidence[prefix+"fw_class"] = entry.Class
+		evidence[prefix+"fw_type"] = entry.Type
+		evidence[prefix+"fw_version"] = strconv.FormatUint(uint64(entry.Version), 10)
+		evidence[prefix+"lowest_supported_fw_version"] = strconv.FormatUint(uint64(entry.LowestSupportedVersion), 10)
+		evidence[prefix+"last_attempt_version"] = strconv.FormatUint(uint64(entry.LastAttemptVersion), 10)
+		evidence[prefix+"last_attempt_status"] = entry.LastAttemptStatus
+		evidence[prefix+"capsule_flags"] = entry.CapsuleFlags
+	}
+
+	return evidence, nil
+}
+
+// GetEntries reads the firmware resource entries of the ESRT. It returns no entries when the
+// firmware does not provide an ESRT.
+func GetEntries() ([]Entry, error) {
+	dirs, err := os.ReadDir(esrtEntriesDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", esrtEntriesDir, err)
+	}
+
+	entries := make([]Entry, 0, len(dirs))
+
+	for _, dir := range dirs {
+		path := filepath.Join(esrtEntriesDir, dir.Name())
+
+		fwType := utils.ReadSysfsValue(filepath.Join(path, "fw_type"))
+		if name, ok := firmwareTypes[fwType]; ok {
+			fwType = name
+		}
+
+		status := utils.ReadSysfsValue(filepath.Join(path, "last_attempt_status"))
+		if name, ok := lastAttemptStatuses[status]; ok {
+			status = name
+		}
+
+		entries = append(entries, Entry{
+			Name:                   dir.Name(),
+			Class:                  utils.ReadSysfsValue(filepath.Join(path, "fw_class")),
+			Type:                   fwType,
+			Version:                readSysfsUint(filepath.Join(path, "fw_version")),
+			LowestSupportedVersion: readSysfsUint(filepath.Join(path, "lowest_supported_fw_version")),
+			LastAttemptVersion:     readSysfsUint(filepath.Join(path, "last_attempt_version")),
+			LastAttemptStatus:      status,
+			CapsuleFlags:           utils.ReadSysfsValue(filepath.Join(path, "capsule_flags")),
+		})
+	}
+
+	return entries, nil
+}
+
+func readSysfsUint(path string) uint32 {
+	value, err := strconv.ParseUint(utils.ReadSysfsValue(path), 0, 32)
+	if err != nil {
+		return 0
+	}
+
+	return uint32(value)
+}
@@ -14,6 +14,7 @@ import (
 
 const (
 	cmdArgAttestationServer = "kommmodity.attestation.server"
+	cmdArgUKIPath           = "kommodity.attestation.uki"
 )
 
 // Execute runs the attestation process based on the provided command-line arguments.
@@ -32,7 +33,9 @@ func Execute(args map[string]string) error {
 		return fmt.Errorf("failed to get nonce: %w", err)
 	}
 
-	report := report.NewAllAttestableReport()
+	report := report.NewAllAttestableReport(report.Config{
+		UKIPath: args[cmdArgUKIPath],
+	})
 
 	responseReport, err := report.Generate([]byte(nonce.Payload.Nonce))
 	if err != nil {
@@ -0,0 +1,212 @@
+package exposure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/mounts"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	devMemPath            = "/dev/mem"
+	devKmemPath           = "/dev/kmem"
+	devPortPath           = "/dev/port"
+	procKcorePath         = "/proc/kcore"
+	kprobesEnabledPath    = "/sys/kernel/debug/kprobes/enabled"
+	kprobePMUPath         = "/sys/bus/event_source/devices/kprobe"
+	kexecLoadDisabledPath = "/proc/sys/kernel/kexec_load_disabled"
+	powerStatePath        = "/sys/power/state"
+	procSwapsPath         = "/proc/swaps"
+	corePatternPath       = "/proc/sys/kernel/core_pattern"
+	suidDumpablePath      = "/proc/sys/fs/suid_dumpable"
+	worldWritableMask     = 0o002
+	otherPermissionsMask  = 0o007
+	hibernationState      = "disk"
+	corePatternPipe       = "|"
+	enabledValue          = "1"
+	suidDumpableDisabled  = "0"
+	mountModeOptionPrefix = "mode="
+	// hostRoot is the root filesystem of the host, through which the pseudo filesystems mounted on the host
+	// are reached from the extension container.
+	hostRoot = "/proc/1/root"
+)
+
+// trustedCoreHelpers lists the crash handlers core dumps may be piped to.
+//
+//nolint:gochecknoglobals
+var trustedCoreHelpers = []string{
+	"/usr/lib/systemd/systemd-coredump",
+	"/lib/systemd/systemd-coredump",
+	"/usr/share/apport/apport",
+	"/usr/libexec/abrt-hook-ccpp",
+}
+
+// checkPseudoFS fails when the pseudo filesystem is mounted world-writable or with a mode option granting
+// access to other users. The root of configfs is always 0755, so read access alone is not flagged.
+func checkPseudoFS(fsType string, hostMounts []mounts.Mount) utils.CheckResult {
+	open := make([]string, 0)
+
+	for _, m := range hostMounts {
+		if m.FSType != fsType {
+			continue
+		}
+
+		info, err := os.Stat(filepath.Join(hostRoot, m.MountPoint))
+		if err == nil && info.Mode().Perm()&worldWritableMask != 0 {
+			open = append(open, fmt.Sprintf("%s (%s)", m.MountPoint, info.Mode().Perm()))
+
+			continue
+		}
+
+		if slices.ContainsFunc(m.SuperOptions, isOpenModeOption) {
+			open = append(open, m.MountPoint)
+		}
+	}
+
+	if len(open) > 0 {
+		return utils.Fail(fsType, fsType+" mounted with open permissions at "+strings.Join(open, ", "))
+	}
+
+	return utils.Pass(fsType)
+}
+
+// isOpenModeOption reports whether the mount option grants permissions to other users, e.g. "mode=755".
+func isOpenModeOption(option string) bool {
+	value, found := strings.CutPrefix(option, mountModeOptionPrefix)
+	if !found {
+		return false
+	}
+
+	mode, err := strconv.ParseUint(value, 8, 32)
+
+	return err == nil && mode&otherPermissionsMask != 0
+}
+
+// checkDevice fails when the device can be opened, which kernel lockdown and STRICT_DEVMEM prevent.
+func checkDevice(name string, path string) utils.CheckResult {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return utils.Pass(name)
+	} else if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	_ = file.Close()
+
+	return utils.Fail(name, path+" is accessible")
+}
+
+func checkKprobes() utils.CheckResult {
+	const name = "kprobes"
+
+	value, err := utils.ReadValue(kprobesEnabledPath)
+	if err == nil {
+		if value == enabledValue {
+			return utils.Fail(name, "kprobes are enabled")
+		}
+
+		return utils.Pass(name)
+	}
+
+	_, err = os.Stat(kprobePMUPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return utils.Pass(name)
+	}
+
+	return utils.Skip(name, "kprobes are built in but "+kprobesEnabledPath+" is not readable")
+}
+
+func checkKexec() utils.CheckResult {
+	const name = "kexec-load"
+
+	value, err := utils.ReadValue(kexecLoadDisabledPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return utils.Pass(name)
+	} else if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	if value != enabledValue {
+		return utils.Fail(name, "kexec_load is not disabled")
+	}
+
+	return utils.Pass(name)
+}
+
+func checkHibernation() utils.CheckResult {
+	const name = "hibernation"
+
+	value, err := utils.ReadValue(powerStatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return utils.Pass(name)
+	} else if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	if slices.Contains(strings.Fields(value), hibernationState) {
+		return utils.Fail(name, "hibernation to disk is available")
+	}
+
+	return utils.Pass(name)
+}
+
+func checkSwap() utils.CheckResult {
+	const name = "swap"
+
+	value, err := utils.ReadValue(procSwapsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return utils.Pass(name)
+	} else if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	// The first line of /proc/swaps is a header.
+	lines := strings.Split(value, "\n")
+	if len(lines) > 1 {
+		return utils.Fail(name, fmt.Sprintf("%d swap devices are active", len(lines)-1))
+	}
+
+	return utils.Pass(name)
+}
+
+// checkCorePattern fails when core dumps are piped to a helper other than a known crash handler, as the
+// helper runs as root for every crashing process.
+func checkCorePattern() utils.CheckResult {
+	const name = "core-pattern"
+
+	value, err := utils.ReadValue(corePatternPath)
+	if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	helper, piped := strings.CutPrefix(value, corePatternPipe)
+	if piped {
+		fields := strings.Fields(helper)
+		if len(fields) == 0 || !slices.Contains(trustedCoreHelpers, fields[0]) {
+			return utils.Fail(name, "core dumps are piped to untrusted helper "+helper)
+		}
+	}
+
+	return utils.Pass(name)
+}
+
+func checkSuidDumpable() utils.CheckResult {
+	const name = "suid-dumpable"
+
+	value, err := utils.ReadValue(suidDumpablePath)
+	if err != nil {
+		return utils.Skip(name, err.Error())
+	}
+
+	if value != suidDumpableDisabled {
+		return utils.Fail(name, "core dumps of setuid processes are enabled ("+value+")")
+	}
+
+	return utils.Pass(name)
+}
@@ -0,0 +1,91 @@
+// Package exposure provides utilities to attest that the debug and attack-surface interfaces of the kernel
+// are not exposed on the machine.
+package exposure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/mounts"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// Attestable implements the report.Attestable interface for the debug and attack-surface exposure.
+type Attestable struct {
+	findings  []utils.CheckResult
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "exposure"
+}
+
+// Measure returns the measurement of the attack-surface findings.
+func (a *Attestable) Measure() (string, error) {
+	findings := GetFindings()
+
+	data, err := yaml.Marshal(findings)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal exposure findings: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.findings = findings
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about each attack-surface finding.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"findings_count": strconv.Itoa(len(a.findings)),
+		"timestamp":      a.timestamp,
+	}
+
+	results := make(map[string]int)
+
+	for _, finding := range a.findings {
+		prefix := "finding_" + strings.ReplaceAll(finding.Name, "-", "_")
+		evidence[prefix] = finding.Result
+
+		if finding.Reason != "" {
+			evidence[prefix+"_reason"] = finding.Reason
+		}
+
+		results[finding.Result]++
+	}
+
+	for _, result := range []string{utils.ResultPass, utils.ResultFail, utils.ResultSkipped} {
+		evidence[result+"_count"] = strconv.Itoa(results[result])
+	}
+
+	return evidence, nil
+}
+
+// GetFindings runs all attack-surface checks. A check passes when the interface is not exposed and is
+// skipped when its exposure cannot be determined.
+func GetFindings() []utils.CheckResult {
+	hostMounts, err := mounts.GetMounts()
+	if err != nil {
+		hostMounts = []mounts.Mount{}
+	}
+
+	return []utils.CheckResult{
+		checkPseudoFS("debugfs", hostMounts),
+		checkPseudoFS("tracefs", hostMounts),
+		checkPseudoFS("configfs", hostMounts),
+		checkDevice("dev-mem", devMemPath),
+		checkDevice("dev-kmem", devKmemPath),
+		checkDevice("dev-port", devPortPath),
+		checkDevice("proc-kcore", procKcorePath),
+		checkKprobes(),
+		checkKexec(),
+		checkHibernation(),
+		checkSwap(),
+		checkCorePattern(),
+		checkSuidDumpable(),
+	}
+}
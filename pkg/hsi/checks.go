@@ -0,0 +1,200 @@
+package hsi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/acpi"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/hwsecurity"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/lockdown"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/secureboot"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/vulnerabilities"
+)
+
+const (
+	tpmVersionMajorPath = "/sys/class/tpm/tpm0/tpm_version_major"
+	tpmVersion2         = "2"
+	lockdownNone        = "none"
+)
+
+// spiWriteablePaths lists the module parameters through which the Intel SPI driver exposes whether
+// the flash can be written from the OS.
+//
+//nolint:gochecknoglobals
+var spiWriteablePaths = []string{
+	"/sys/module/spi_intel/parameters/writeable",
+	"/sys/module/intel_spi/parameters/writeable",
+}
+
+func runChecks() []Check {
+	hardware, err := hwsecurity.GetHardwareSecurityState()
+
+	return []Check{
+		{Level: LevelCritical, CheckResult: checkTPM()},
+		{Level: LevelCritical, CheckResult: checkSecureBoot()},
+		{Level: LevelCritical, CheckResult: checkSPIWriteProtection()},
+		{Level: LevelCritical, CheckResult: checkCPUMitigations()},
+		{Level: LevelImportant, CheckResult: checkIOMMU(hardware, err)},
+		{Level: LevelImportant, CheckResult: checkLockdown()},
+		{Level: LevelTheoretical, CheckResult: checkDMAProtection()},
+		{
+			Level:       LevelTheoretical,
+			CheckResult: checkCPUFlags("cet", hardware, err, []string{"ibt", "user_shstk"}, false),
+		},
+		{
+			Level:       LevelSystemProtection,
+			CheckResult: checkCPUFlags("smep-smap", hardware, err, []string{"smep", "smap"}, true),
+		},
+		{
+			Level:       LevelSystemProtection,
+			CheckResult: checkCPUFlags("memory-encryption", hardware, err, []string{"sme", "tme"}, false),
+		},
+	}
+}
+
+func checkTPM() utils.CheckResult {
+	const name = "tpm-v2"
+
+	data, err := os.ReadFile(tpmVersionMajorPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return utils.Fail(name, "no TPM device found")
+	} else if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read TPM version: %v", err))
+	}
+
+	version := strings.TrimSpace(string(data))
+	if version != tpmVersion2 {
+		return utils.Fail(name, fmt.Sprintf("TPM version %s is not 2.0", version))
+	}
+
+	return utils.Pass(name)
+}
+
+func checkSecureBoot() utils.CheckResult {
+	const name = "secure-boot"
+
+	enabled, err := secureboot.IsSecureBootEnabled()
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to determine Secure Boot status: %v", err))
+	}
+
+	if !enabled {
+		return utils.Fail(name, "Secure Boot is disabled")
+	}
+
+	return utils.Pass(name)
+}
+
+func checkSPIWriteProtection() utils.CheckResult {
+	const name = "spi-write-protection"
+
+	for _, path := range spiWriteablePaths {
+		//nolint:gosec // Sysfs path is controlled
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		if strings.TrimSpace(string(data)) == "Y" {
+			return utils.Fail(name, "SPI flash is writeable from the OS")
+		}
+
+		return utils.Pass(name)
+	}
+
+	return utils.Skip(name, "SPI write protection is not exposed through sysfs")
+}
+
+func checkCPUMitigations() utils.CheckResult {
+	const name = "cpu-mitigations"
+
+	state, err := vulnerabilities.GetVulnerabilityState()
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read CPU vulnerabilities: %v", err))
+	}
+
+	vulnerable := make([]string, 0)
+
+	for _, vulnerability := range state.Vulnerabilities {
+		if vulnerability.Status == vulnerabilities.StatusVulnerable {
+			vulnerable = append(vulnerable, vulnerability.Name)
+		}
+	}
+
+	if len(vulnerable) > 0 {
+		return utils.Fail(name, "CPU is vulnerable to "+strings.Join(vulnerable, ", "))
+	}
+
+	return utils.Pass(name)
+}
+
+func checkIOMMU(hardware *hwsecurity.State, err error) utils.CheckResult {
+	const name = "iommu"
+
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read IOMMU state: %v", err))
+	}
+
+	if !hardware.IOMMU.Active {
+		return utils.Fail(name, "no IOMMU is active")
+	}
+
+	return utils.Pass(name)
+}
+
+func checkLockdown() utils.CheckResult {
+	const name = "kernel-lockdown"
+
+	mode, err := lockdown.GetKernelLockdownMode()
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read kernel lockdown mode: %v", err))
+	}
+
+	mode = strings.Trim(mode, "[]")
+	if mode == "" || mode == lockdownNone {
+		return utils.Fail(name, "kernel lockdown is disabled")
+	}
+
+	return utils.Pass(name)
+}
+
+func checkDMAProtection() utils.CheckResult {
+	const name = "pre-boot-dma-protection"
+
+	state, err := acpi.GetACPIState()
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read ACPI tables: %v", err))
+	}
+
+	if !state.DMAProtection {
+		return utils.Fail(name, "firmware does not advertise DMA protection in DMAR or IVRS")
+	}
+
+	return utils.Pass(name)
+}
+
+// checkCPUFlags passes when any of the given CPU flags is present, or all of them when requireAll is set.
+func checkCPUFlags(
+	name string, hardware *hwsecurity.State, err error, flags []string, requireAll bool,
+) utils.CheckResult {
+	if err != nil {
+		return utils.Fail(name, fmt.Sprintf("failed to read CPU flags: %v", err))
+	}
+
+	missing := make([]string, 0)
+
+	for _, flag := range flags {
+		if !hardware.CPUFlags[flag] {
+			missing = append(missing, flag)
+		}
+	}
+
+	if len(missing) == len(flags) || (requireAll && len(missing) > 0) {
+		return utils.Fail(name, "missing CPU flags "+strings.Join(missing, ", "))
+	}
+
+	return utils.Pass(name)
+}
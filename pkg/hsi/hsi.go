@@ -0,0 +1,106 @@
+// Package hsi provides a platform security level computed from the attested posture, modeled on the
+// Host Security ID (HSI) levels of fwupd.
+package hsi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// LevelCritical groups the checks every machine is expected to pass (HSI-1).
+	LevelCritical = 1
+	// LevelImportant groups the checks protecting against runtime attacks (HSI-2).
+	LevelImportant = 2
+	// LevelTheoretical groups the checks protecting against theoretical attacks (HSI-3).
+	LevelTheoretical = 3
+	// LevelSystemProtection groups the checks for defense in depth features (HSI-4).
+	LevelSystemProtection = 4
+)
+
+// Check represents the result of a single platform security check at its level.
+type Check struct {
+	utils.CheckResult `yaml:",inline"`
+
+	Level int `yaml:"level"`
+}
+
+// Score represents the computed platform security level and the checks it is derived from.
+type Score struct {
+	Level  int     `yaml:"level"`
+	Checks []Check `yaml:"checks"`
+}
+
+// Attestable implements the report.Attestable interface for the platform security level.
+type Attestable struct {
+	score        *Score
+	checksDigest string
+	timestamp    string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "hsi"
+}
+
+// Measure returns the measurement of the platform security level and the check results.
+func (a *Attestable) Measure() (string, error) {
+	score := GetScore()
+
+	checks, err := yaml.Marshal(score.Checks)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal HSI checks: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.score = score
+	a.checksDigest = utils.EncodeMeasurement(checks)
+
+	return utils.EncodeMeasurement([]byte(strconv.Itoa(score.Level) + ":" + a.checksDigest)), nil
+}
+
+// Evidence returns metadata about the platform security level and each check.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"level":         strconv.Itoa(a.score.Level),
+		"hsi":           "HSI:" + strconv.Itoa(a.score.Level),
+		"checks_digest": a.checksDigest,
+		"checks_count":  strconv.Itoa(len(a.score.Checks)),
+		"timestamp":     a.timestamp,
+	}
+
+	for _, check := range a.score.Checks {
+		prefix := "check_" + strings.ReplaceAll(check.Name, "-", "_")
+		evidence[prefix] = check.Result
+		evidence[prefix+"_level"] = strconv.Itoa(check.Level)
+
+		if check.Reason != "" {
+			evidence[prefix+"_reason"] = check.Reason
+		}
+	}
+
+	return evidence, nil
+}
+
+// GetScore runs all platform security checks and computes the level. The level is the highest one
+// for which every check at that level and below passed or was skipped.
+func GetScore() *Score {
+	checks := runChecks()
+
+	level := LevelSystemProtection
+
+	for _, check := range checks {
+		if check.Result == utils.ResultFail && check.Level <= level {
+			level = check.Level - 1
+		}
+	}
+
+	return &Score{
+		Level:  level,
+		Checks: checks,
+	}
+}
@@ -0,0 +1,203 @@
+// Package hwsecurity provides utilities to attest the CPU and platform hardware security features of the machine.
+package hwsecurity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/cpuinfo"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	iommuClassDir          = "/sys/class/iommu"
+	iommuGroupsDir         = "/sys/kernel/iommu_groups"
+	thunderboltDevicesDir  = "/sys/bus/thunderbolt/devices"
+	thunderboltDomainGlob  = thunderboltDevicesDir + "/domain*"
+	iommuGroupTypeFileName = "type"
+)
+
+// securityFlags lists the /proc/cpuinfo flags of the memory encryption, supervisor protection and
+// control-flow enforcement features that are reported.
+//
+//nolint:gochecknoglobals
+var securityFlags = []string{
+	"sme",        // AMD Secure Memory Encryption
+	"sev",        // AMD Secure Encrypted Virtualization
+	"sev_es",     // AMD SEV Encrypted State
+	"sev_snp",    // AMD SEV Secure Nested Paging
+	"tme",        // Intel Total Memory Encryption
+	"smep",       // Supervisor Mode Execution Prevention
+	"smap",       // Supervisor Mode Access Prevention
+	"umip",       // User Mode Instruction Prevention
+	"user_shstk", // CET shadow stack
+	"ibt",        // CET indirect branch tracking
+}
+
+// State represents the hardware security features of the machine.
+type State struct {
+	CPUFlags    map[string]bool     `yaml:"cpuFlags"`
+	IOMMU       IOMMU               `yaml:"iommu"`
+	Thunderbolt []ThunderboltDomain `yaml:"thunderbolt"`
+}
+
+// IOMMU represents the state of the IOMMU and its DMA translation modes.
+type IOMMU struct {
+	Active   bool     `yaml:"active"`
+	Units    []string `yaml:"units"`
+	Groups   int      `yaml:"groups"`
+	DMAModes []string `yaml:"dmaModes"`
+}
+
+// ThunderboltDomain represents the security level of a Thunderbolt domain.
+type ThunderboltDomain struct {
+	Name               string `yaml:"name"`
+	Security           string `yaml:"security"`
+	IOMMUDMAProtection bool   `yaml:"iommuDmaProtection"`
+}
+
+// Attestable implements the report.Attestable interface for hardware security features.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "hardware-security"
+}
+
+// Measure returns the measurement of the hardware security features.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetHardwareSecurityState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get hardware security state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal hardware security state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the hardware security features.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"iommu_active":              strconv.FormatBool(a.state.IOMMU.Active),
+		"iommu_units":               strings.Join(a.state.IOMMU.Units, ","),
+		"iommu_groups_count":        strconv.Itoa(a.state.IOMMU.Groups),
+		"iommu_dma_modes":           strings.Join(a.state.IOMMU.DMAModes, ","),
+		"thunderbolt_domains_count": strconv.Itoa(len(a.state.Thunderbolt)),
+		"timestamp":                 a.timestamp,
+	}
+
+	for flag, present := range a.state.CPUFlags {
+		evidence["cpu_flag_"+flag] = strconv.FormatBool(present)
+	}
+
+	for i, domain := range a.state.Thunderbolt {
+		prefix := fmt.Sprintf("thunderbolt_domain_%d_", i)
+		evidence[prefix+"name"] = domain.Name
+		evidence[prefix+"security"] = domain.Security
+		evidence[prefix+"iommu_dma_protection"] = strconv.FormatBool(domain.IOMMUDMAProtection)
+	}
+
+	return evidence, nil
+}
+
+// GetHardwareSecurityState reads the CPU security flags, the IOMMU state and the Thunderbolt security levels.
+func GetHardwareSecurityState() (*State, error) {
+	processors, err := cpuinfo.ParseProcCPUInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpuinfo: %w", err)
+	}
+
+	iommu, err := getIOMMU()
+	if err != nil {
+		return nil, err
+	}
+
+	cpuFlags := make(map[string]bool, len(securityFlags))
+
+	var flags map[string]bool
+	if len(processors) > 0 {
+		flags = processors[0].Flags()
+	}
+
+	for _, flag := range securityFlags {
+		cpuFlags[flag] = flags[flag]
+	}
+
+	return &State{
+		CPUFlags:    cpuFlags,
+		IOMMU:       *iommu,
+		Thunderbolt: getThunderboltDomains(),
+	}, nil
+}
+
+func getIOMMU() (*IOMMU, error) {
+	iommu := &IOMMU{
+		Units:    make([]string, 0),
+		DMAModes: make([]string, 0),
+	}
+
+	units, err := os.ReadDir(iommuClassDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", iommuClassDir, err)
+	}
+
+	for _, unit := range units {
+		iommu.Units = append(iommu.Units, unit.Name())
+	}
+
+	groups, err := os.ReadDir(iommuGroupsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", iommuGroupsDir, err)
+	}
+
+	modes := make(map[string]bool)
+
+	for _, group := range groups {
+		mode := utils.ReadSysfsValue(filepath.Join(iommuGroupsDir, group.Name(), iommuGroupTypeFileName))
+		if mode != "" {
+			modes[mode] = true
+		}
+	}
+
+	for mode := range modes {
+		iommu.DMAModes = append(iommu.DMAModes, mode)
+	}
+
+	sort.Strings(iommu.DMAModes)
+
+	iommu.Groups = len(groups)
+	iommu.Active = len(iommu.Units) > 0 && iommu.Groups > 0
+
+	return iommu, nil
+}
+
+func getThunderboltDomains() []ThunderboltDomain {
+	domains := make([]ThunderboltDomain, 0)
+
+	paths, _ := filepath.Glob(thunderboltDomainGlob)
+	for _, path := range paths {
+		domains = append(domains, ThunderboltDomain{
+			Name:               filepath.Base(path),
+			Security:           utils.ReadSysfsValue(filepath.Join(path, "security")),
+			IOMMUDMAProtection: utils.ReadSysfsValue(filepath.Join(path, "iommu_dma_protection")) == "1",
+		})
+	}
+
+	return domains
+}
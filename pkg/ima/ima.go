@@ -0,0 +1,256 @@
+// Package ima provides utilities to attest the IMA (Integrity Measurement Architecture) and EVM policy.
+package ima
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/cmdline"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	securityFSPath          = "/sys/kernel/security"
+	imaDir                  = securityFSPath + "/ima"
+	imaPolicyPath           = imaDir + "/policy"
+	imaMeasurementsPath     = imaDir + "/ascii_runtime_measurements"
+	imaMeasurementsCount    = imaDir + "/runtime_measurements_count"
+	evmPath                 = securityFSPath + "/evm"
+	evmIntegrityPath        = securityFSPath + "/integrity/evm/evm"
+	imaHashParam            = "ima_hash"
+	imaAppraiseParam        = "ima_appraise"
+	imaPolicyParam          = "ima_policy"
+	measurementHashField    = 3
+	measurementHashSplitter = ":"
+
+	// EVM initialization flags as defined in security/integrity/evm/evm.h.
+	evmInitHMAC           = 0x00000001
+	evmInitX509           = 0x00000002
+	evmAllowMetadataWrite = 0x00000004
+)
+
+//nolint:gochecknoglobals
+var policyActions = []string{
+	"measure", "dont_measure", "appraise", "dont_appraise", "audit", "hash", "dont_hash",
+}
+
+// Policy represents the active IMA policy.
+type Policy struct {
+	Readable bool           `yaml:"readable"`
+	Hash     string         `yaml:"hash"`
+	Rules    []string       `yaml:"rules"`
+	Actions  map[string]int `yaml:"actions"`
+}
+
+// EVM represents the EVM initialization state. The kernel masks EVM_SETUP_COMPLETE out of the state it
+// exposes in securityfs, so whether the EVM setup is complete cannot be read from userspace.
+type EVM struct {
+	Enabled              bool `yaml:"enabled"`
+	HMAC                 bool `yaml:"hmac"`
+	X509                 bool `yaml:"x509"`
+	AllowMetadataWrites  bool `yaml:"allowMetadataWrites"`
+	InitializationStatus int  `yaml:"initializationStatus"`
+}
+
+// State represents the IMA policy, the EVM state and the IMA hash algorithm.
+type State struct {
+	Enabled       bool   `yaml:"enabled"`
+	HashAlgorithm string `yaml:"hashAlgorithm"`
+	AppraiseMode  string `yaml:"appraiseMode"`
+	BuiltinPolicy string `yaml:"builtinPolicy"`
+	Policy        Policy `yaml:"policy"`
+	EVM           EVM    `yaml:"evm"`
+}
+
+// Attestable implements the report.Attestable interface for the IMA and EVM policy.
+type Attestable struct {
+	state        *State
+	measurements string
+	timestamp    string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "ima"
+}
+
+// Measure returns the measurement of the IMA policy and the EVM state.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetIMAState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get IMA state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal IMA state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+	a.measurements = utils.ReadSysfsValue(imaMeasurementsCount)
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the IMA policy and the EVM state.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"ima_enabled":        strconv.FormatBool(a.state.Enabled),
+		"hash_algorithm":     a.state.HashAlgorithm,
+		"appraise_mode":      a.state.AppraiseMode,
+		"builtin_policy":     a.state.BuiltinPolicy,
+		"policy_readable":    strconv.FormatBool(a.state.Policy.Readable),
+		"policy_hash":        a.state.Policy.Hash,
+		"policy_rules_count": strconv.Itoa(len(a.state.Policy.Rules)),
+		"measurements_count": a.measurements,
+		"evm_enabled":        strconv.FormatBool(a.state.EVM.Enabled),
+		"evm_hmac":           strconv.FormatBool(a.state.EVM.HMAC),
+		"evm_x509":           strconv.FormatBool(a.state.EVM.X509),
+		"evm_metadata_write": strconv.FormatBool(a.state.EVM.AllowMetadataWrites),
+		"timestamp":          a.timestamp,
+	}
+
+	for _, action := range policyActions {
+		evidence[action+"_rules"] = strconv.Itoa(a.state.Policy.Actions[action])
+	}
+
+	return evidence, nil
+}
+
+// GetIMAState reads the active IMA policy, the EVM initialization state and the IMA hash algorithm.
+func GetIMAState() (*State, error) {
+	state := &State{
+		Policy: Policy{
+			Rules:   make([]string, 0),
+			Actions: make(map[string]int),
+		},
+	}
+
+	_, err := os.Stat(imaDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", imaDir, err)
+	}
+
+	state.Enabled = true
+
+	params, err := cmdline.ParseProcCmdline()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kernel command line: %w", err)
+	}
+
+	state.AppraiseMode = params[imaAppraiseParam]
+	state.BuiltinPolicy = params[imaPolicyParam]
+
+	state.HashAlgorithm = params[imaHashParam]
+	if state.HashAlgorithm == "" {
+		state.HashAlgorithm = getMeasurementHashAlgorithm()
+	}
+
+	state.Policy, err = GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	state.EVM, err = GetEVMState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// GetPolicy reads the active IMA policy rules. The policy is only readable when the kernel is built
+// with CONFIG_IMA_READ_POLICY, otherwise it is reported as not readable.
+func GetPolicy() (Policy, error) {
+	policy := Policy{
+		Rules:   make([]string, 0),
+		Actions: make(map[string]int),
+	}
+
+	data, err := os.ReadFile(imaPolicyPath)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return policy, nil
+	} else if err != nil {
+		return policy, fmt.Errorf("failed to read %s: %w", imaPolicyPath, err)
+	}
+
+	policy.Readable = true
+	policy.Hash = utils.EncodeMeasurement(data)
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		rule := strings.TrimSpace(line)
+		if rule == "" {
+			continue
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+		policy.Actions[strings.Fields(rule)[0]]++
+	}
+
+	return policy, nil
+}
+
+// GetEVMState reads the EVM initialization state.
+func GetEVMState() (EVM, error) {
+	evm := EVM{}
+
+	value := utils.ReadSysfsValue(evmPath)
+	if value == "" {
+		value = utils.ReadSysfsValue(evmIntegrityPath)
+	}
+
+	if value == "" {
+		return evm, nil
+	}
+
+	status, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return evm, fmt.Errorf("failed to parse EVM state %q: %w", value, err)
+	}
+
+	evm.Enabled = true
+	evm.InitializationStatus = int(status)
+	evm.HMAC = status&evmInitHMAC != 0
+	evm.X509 = status&evmInitX509 != 0
+	evm.AllowMetadataWrites = status&evmAllowMetadataWrite != 0
+
+	return evm, nil
+}
+
+// getMeasurementHashAlgorithm returns the file hash algorithm of the first entry of the IMA measurement list,
+// which is recorded as "<algorithm>:<digest>" by the ima-ng and ima-sig templates.
+func getMeasurementHashAlgorithm() string {
+	file, err := os.Open(imaMeasurementsPath)
+	if err != nil {
+		return ""
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return ""
+	}
+
+	fields := strings.Fields(scanner.Text())
+	if len(fields) <= measurementHashField {
+		return ""
+	}
+
+	algorithm, _, found := strings.Cut(fields[measurementHashField], measurementHashSplitter)
+	if !found {
+		return ""
+	}
+
+	return algorithm
+}
@@ -0,0 +1,9 @@
+// Package kernel provides error definitions for kernel identity operations.
+package kernel
+
+import "errors"
+
+var (
+	// ErrInvalidNote is returned when the kernel notes cannot be parsed.
+	ErrInvalidNote = errors.New("invalid ELF note")
+)
@@ -0,0 +1,120 @@
+// Package kernel provides utilities to attest the identity of the running kernel and the current boot.
+package kernel
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	procVersionPath = "/proc/version"
+	bootIDPath      = "/proc/sys/kernel/random/boot_id"
+)
+
+// State represents the identity of the running kernel and the current boot.
+type State struct {
+	Release     string `yaml:"release"`
+	Version     string `yaml:"version"`
+	Machine     string `yaml:"machine"`
+	ProcVersion string `yaml:"procVersion"`
+	BuildID     string `yaml:"buildID"`
+	BootID      string `yaml:"-"`
+	BootTime    int64  `yaml:"-"`
+	Uptime      int64  `yaml:"-"`
+}
+
+// Attestable implements the report.Attestable interface for the kernel identity.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "kernel"
+}
+
+// Measure returns the measurement of the identity of the running kernel image. The boot ID and times
+// change on every boot and are only reported as evidence.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetKernelState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kernel state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal kernel state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the running kernel and the current boot.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	return map[string]string{
+		"release":      a.state.Release,
+		"version":      a.state.Version,
+		"machine":      a.state.Machine,
+		"proc_version": a.state.ProcVersion,
+		"build_id":     a.state.BuildID,
+		"boot_id":      a.state.BootID,
+		"boot_time":    strconv.FormatInt(a.state.BootTime, 10),
+		"uptime":       strconv.FormatInt(a.state.Uptime, 10),
+		"timestamp":    a.timestamp,
+	}, nil
+}
+
+// GetKernelState reads the uname of the running kernel, its build ID, the boot ID, the boot time
+// and the uptime in seconds.
+func GetKernelState() (*State, error) {
+	var uname unix.Utsname
+
+	err := unix.Uname(&uname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uname: %w", err)
+	}
+
+	procVersion, err := utils.ReadValue(procVersionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	buildID, err := GetBuildID()
+	if err != nil {
+		return nil, err
+	}
+
+	bootID, err := utils.ReadValue(bootIDPath)
+	if err != nil {
+		return nil, err
+	}
+
+	uptime, err := utils.Uptime()
+	if err != nil {
+		return nil, err
+	}
+
+	bootTime, err := utils.BootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Release:     unix.ByteSliceToString(uname.Release[:]),
+		Version:     unix.ByteSliceToString(uname.Version[:]),
+		Machine:     unix.ByteSliceToString(uname.Machine[:]),
+		ProcVersion: procVersion,
+		BuildID:     buildID,
+		BootID:      bootID,
+		BootTime:    bootTime.Unix(),
+		Uptime:      int64(uptime.Seconds()),
+	}, nil
+}
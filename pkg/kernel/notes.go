@@ -0,0 +1,51 @@
+package kernel
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+const (
+	notesPath       = "/sys/kernel/notes"
+	noteHeaderSize  = 12
+	noteAlignment   = 4
+	noteTypeBuildID = 3
+	noteNameGNU     = "GNU\x00"
+)
+
+// GetBuildID returns the GNU build ID of the running kernel image from the ELF notes the kernel exports.
+// It returns an empty string when the kernel was built without a build ID.
+func GetBuildID() (string, error) {
+	data, err := os.ReadFile(notesPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", notesPath, err)
+	}
+
+	for len(data) >= noteHeaderSize {
+		nameSize := int(binary.NativeEndian.Uint32(data[0:4]))
+		descSize := int(binary.NativeEndian.Uint32(data[4:8]))
+		kind := binary.NativeEndian.Uint32(data[8:12])
+
+		nameEnd := noteHeaderSize + align(nameSize)
+		descEnd := nameEnd + align(descSize)
+
+		if descEnd > len(data) {
+			return "", fmt.Errorf("%w: note size exceeds %s", ErrInvalidNote, notesPath)
+		}
+
+		name := string(data[noteHeaderSize : noteHeaderSize+nameSize])
+		if kind == noteTypeBuildID && name == noteNameGNU {
+			return hex.EncodeToString(data[nameEnd : nameEnd+descSize]), nil
+		}
+
+		data = data[descEnd:]
+	}
+
+	return "", nil
+}
+
+func align(size int) int {
+	return (size + noteAlignment - 1) &^ (noteAlignment - 1)
+}
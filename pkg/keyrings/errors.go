@@ -0,0 +1,9 @@
+// Package keyrings provides error definitions for kernel keyring operations.
+package keyrings
+
+import "errors"
+
+var (
+	// ErrInvalidKeyDescription is returned when the kernel returns a key description that cannot be parsed.
+	ErrInvalidKeyDescription = errors.New("invalid key description")
+)
@@ -0,0 +1,310 @@
+// Package keyrings provides utilities to attest the kernel trusted keyrings.
+package keyrings
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	procKeysPath      = "/proc/keys"
+	procKeyUsersPath  = "/proc/key-users"
+	keyringType       = "keyring"
+	keySerialSize     = 4
+	keysTypeField     = 7
+	keysMinFields     = 9
+	keyUsersMinFields = 3
+	keyDescribeFields = 5
+	keyringSummarySep = ": "
+)
+
+//nolint:gochecknoglobals
+var trustedKeyrings = []string{
+	".builtin_trusted_keys",
+	".secondary_trusted_keys",
+	".platform",
+	".machine",
+	".ima",
+	".blacklist",
+}
+
+// Key represents a key linked into a trusted keyring.
+type Key struct {
+	Serial      int    `yaml:"-"`
+	Type        string `yaml:"type"`
+	Description string `yaml:"description"`
+	Fingerprint string `yaml:"fingerprint"`
+}
+
+// Keyring represents a trusted kernel keyring and the keys linked into it.
+type Keyring struct {
+	Name    string `yaml:"name"`
+	Present bool   `yaml:"present"`
+	Keys    []Key  `yaml:"keys"`
+}
+
+// KeyUser represents the key quota usage of a single user.
+type KeyUser struct {
+	UID  string
+	Keys string
+}
+
+// Attestable implements the report.Attestable interface for the kernel trusted keyrings.
+type Attestable struct {
+	keyrings  []Keyring
+	keyUsers  []KeyUser
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "keyrings"
+}
+
+// Measure returns the measurement of the keys linked into the kernel trusted keyrings.
+func (a *Attestable) Measure() (string, error) {
+	keyrings, err := GetTrustedKeyrings()
+	if err != nil {
+		return "", fmt.Errorf("failed to get trusted keyrings: %w", err)
+	}
+
+	keyUsers, err := GetKeyUsers()
+	if err != nil {
+		return "", fmt.Errorf("failed to get key users: %w", err)
+	}
+
+	data, err := yaml.Marshal(keyrings)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal trusted keyrings: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.keyrings = keyrings
+	a.keyUsers = keyUsers
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the keys linked into the kernel trusted keyrings.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"key_users_count": strconv.Itoa(len(a.keyUsers)),
+		"timestamp":       a.timestamp,
+	}
+
+	for _, user := range a.keyUsers {
+		evidence["key_user_"+user.UID+"_keys"] = user.Keys
+	}
+
+	for _, keyring := range a.keyrings {
+		prefix := strings.TrimPrefix(keyring.Name, ".") + "_"
+		evidence[prefix+"present"] = strconv.FormatBool(keyring.Present)
+		evidence[prefix+"count"] = strconv.Itoa(len(keyring.Keys))
+
+		for i, key := range keyring.Keys {
+			keyPrefix := fmt.Sprintf("%skey_%d_", prefix, i)
+			evidence[keyPrefix+"type"] = key.Type
+			evidence[keyPrefix+"description"] = key.Description
+			evidence[keyPrefix+"fingerprint"] = key.Fingerprint
+		}
+	}
+
+	return evidence, nil
+}
+
+// GetTrustedKeyrings locates the trusted kernel keyrings in /proc/keys and lists the keys linked into each of them.
+func GetTrustedKeyrings() ([]Keyring, error) {
+	serials, err := getKeyringSerials()
+	if err != nil {
+		return nil, err
+	}
+
+	keyrings := make([]Keyring, 0, len(trustedKeyrings))
+
+	for _, name := range trustedKeyrings {
+		keyring := Keyring{
+			Name: name,
+			Keys: make([]Key, 0),
+		}
+
+		serial, ok := serials[name]
+		if ok {
+			keyring.Present = true
+
+			keyring.Keys, err = getKeyringKeys(serial)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read keyring %s: %w", name, err)
+			}
+		}
+
+		keyrings = append(keyrings, keyring)
+	}
+
+	return keyrings, nil
+}
+
+// GetKeyUsers returns the number of keys owned by each user from /proc/key-users.
+func GetKeyUsers() ([]KeyUser, error) {
+	file, err := os.Open(procKeyUsersPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []KeyUser{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", procKeyUsersPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	users := make([]KeyUser, 0)
+
+	// Each line has the format "<uid>: <usage> <nkeys>/<nikeys> <qnkeys>/<maxkeys> <qnbytes>/<maxbytes>".
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < keyUsersMinFields {
+			continue
+		}
+
+		users = append(users, KeyUser{
+			UID:  strings.TrimSuffix(fields[0], ":"),
+			Keys: fields[2],
+		})
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", procKeyUsersPath, err)
+	}
+
+	return users, nil
+}
+
+// getKeyringSerials returns the serials of the keyrings in /proc/keys keyed by keyring description.
+func getKeyringSerials() (map[string]int, error) {
+	file, err := os.Open(procKeysPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]int{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", procKeysPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	serials := make(map[string]int)
+
+	// Each line has the format "<serial> <flags> <usage> <timeout> <perm> <uid> <gid> <type> <description>: <summary>".
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < keysMinFields || fields[keysTypeField] != keyringType {
+			continue
+		}
+
+		description := strings.Join(fields[keysTypeField+1:], " ")
+
+		index := strings.LastIndex(description, keyringSummarySep)
+		if index >= 0 {
+			description = description[:index]
+		}
+
+		serial, err := strconv.ParseInt(fields[0], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		serials[description] = int(serial)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", procKeysPath, err)
+	}
+
+	return serials, nil
+}
+
+// getKeyringKeys returns the keys linked into the keyring sorted by type and description.
+func getKeyringKeys(serial int) ([]Key, error) {
+	size, err := unix.KeyctlBuffer(unix.KEYCTL_READ, serial, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring size: %w", err)
+	}
+
+	buffer := make([]byte, size)
+
+	size, err = unix.KeyctlBuffer(unix.KEYCTL_READ, serial, buffer, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+
+	keys := make([]Key, 0)
+
+	for offset := 0; offset+keySerialSize <= size && offset+keySerialSize <= len(buffer); offset += keySerialSize {
+		id := int(int32(binary.NativeEndian.Uint32(buffer[offset:]))) //nolint:gosec // Key serials are 32-bit
+
+		key, err := describeKey(id)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Type != keys[j].Type {
+			return keys[i].Type < keys[j].Type
+		}
+
+		return keys[i].Description < keys[j].Description
+	})
+
+	return keys, nil
+}
+
+// describeKey returns the type, description and fingerprint of the key. The description of X.509 keys
+// ends with the subject key identifier and blacklist entries end with the blacklisted hash,
+// which are used as the fingerprint.
+func describeKey(serial int) (Key, error) {
+	// The description has the format "<type>;<uid>;<gid>;<perm>;<description>".
+	description, err := unix.KeyctlString(unix.KEYCTL_DESCRIBE, serial)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to describe key %d: %w", serial, err)
+	}
+
+	parts := strings.SplitN(description, ";", keyDescribeFields)
+	if len(parts) != keyDescribeFields {
+		return Key{}, fmt.Errorf("%w: %q", ErrInvalidKeyDescription, description)
+	}
+
+	key := Key{
+		Serial:      serial,
+		Type:        parts[0],
+		Description: parts[keyDescribeFields-1],
+	}
+
+	index := strings.LastIndex(key.Description, ":")
+	if index >= 0 {
+		fingerprint := strings.TrimSpace(key.Description[index+1:])
+
+		_, err := hex.DecodeString(fingerprint)
+		if err == nil {
+			key.Fingerprint = strings.ToLower(fingerprint)
+		}
+	}
+
+	return key, nil
+}
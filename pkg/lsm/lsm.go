@@ -0,0 +1,149 @@
+// Package lsm provides utilities to attest the Linux Security Module stack of the kernel.
+package lsm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/lockdown"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	lsmStackPath          = "/sys/kernel/security/lsm"
+	yamaPtraceScopePath   = "/proc/sys/kernel/yama/ptrace_scope"
+	seccompActionsPath    = "/proc/sys/kernel/seccomp/actions_avail"
+	landlockCreateVersion = 1 // LANDLOCK_CREATE_RULESET_VERSION
+	lsmLandlock           = "landlock"
+	lsmYama               = "yama"
+	lsmBPF                = "bpf"
+	lsmLockdown           = "lockdown"
+)
+
+//nolint:gochecknoglobals
+var yamaModes = map[string]string{
+	"0": "classic",
+	"1": "restricted",
+	"2": "admin-only",
+	"3": "no-attach",
+}
+
+// State represents the Linux Security Module stack and the state of the individual modules.
+type State struct {
+	Stack           []string `yaml:"stack"`
+	LandlockABI     int      `yaml:"landlockAbi"`
+	YamaPtraceScope string   `yaml:"yamaPtraceScope"`
+	LockdownMode    string   `yaml:"lockdownMode"`
+	SeccompActions  []string `yaml:"seccompActions"`
+}
+
+// Attestable implements the report.Attestable interface for the Linux Security Module stack.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "lsm"
+}
+
+// Measure returns the measurement of the Linux Security Module stack.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetLSMState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get LSM state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal LSM state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the Linux Security Module stack.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	return map[string]string{
+		"lsm_stack":         strings.Join(a.state.Stack, ","),
+		"lsm_count":         strconv.Itoa(len(a.state.Stack)),
+		"landlock_enabled":  strconv.FormatBool(a.state.LandlockABI > 0),
+		"landlock_abi":      strconv.Itoa(a.state.LandlockABI),
+		"yama_enabled":      strconv.FormatBool(slices.Contains(a.state.Stack, lsmYama)),
+		"yama_ptrace_scope": a.state.YamaPtraceScope,
+		"yama_mode":         yamaModes[a.state.YamaPtraceScope],
+		"lockdown_enabled":  strconv.FormatBool(slices.Contains(a.state.Stack, lsmLockdown)),
+		"lockdown_mode":     a.state.LockdownMode,
+		"bpf_lsm":           strconv.FormatBool(slices.Contains(a.state.Stack, lsmBPF)),
+		"seccomp_actions":   strings.Join(a.state.SeccompActions, ","),
+		"timestamp":         a.timestamp,
+	}, nil
+}
+
+// GetLSMState reads the ordered LSM stack and the state of Landlock, Yama, lockdown and seccomp.
+func GetLSMState() (*State, error) {
+	stack, err := getStack()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Stack:           stack,
+		YamaPtraceScope: utils.ReadSysfsValue(yamaPtraceScopePath),
+		SeccompActions:  strings.Fields(utils.ReadSysfsValue(seccompActionsPath)),
+	}
+
+	if slices.Contains(stack, lsmLandlock) {
+		state.LandlockABI = getLandlockABI()
+	}
+
+	if slices.Contains(stack, lsmLockdown) {
+		mode, err := lockdown.GetKernelLockdownMode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kernel lockdown mode: %w", err)
+		}
+
+		state.LockdownMode = strings.Trim(mode, "[]")
+	}
+
+	return state, nil
+}
+
+func getStack() ([]string, error) {
+	data, err := os.ReadFile(lsmStackPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", lsmStackPath, err)
+	}
+
+	stack := make([]string, 0)
+
+	for module := range strings.SplitSeq(strings.TrimSpace(string(data)), ",") {
+		if module != "" {
+			stack = append(stack, module)
+		}
+	}
+
+	return stack, nil
+}
+
+// getLandlockABI returns the Landlock ABI version supported by the kernel, or 0 when Landlock is unavailable.
+func getLandlockABI() int {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, landlockCreateVersion)
+	if errno != 0 {
+		return 0
+	}
+
+	return int(abi)
+}
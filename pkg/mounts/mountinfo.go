@@ -0,0 +1,193 @@
+// Package mounts provides utilities to read the mount table of the host and attest it against a mount policy.
+package mounts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// mountInfoPath is the mount table of init, as the mount namespace of the extension itself is the one
+	// of its container.
+	mountInfoPath      = "/proc/1/mountinfo"
+	mountInfoMinFields = 10
+	mountInfoSeparator = "-"
+	// mountInfoOptionalStart is the index of the first optional field, following the mount options.
+	mountInfoOptionalStart = 6
+	// mountInfoTailFields is the number of fields following the separator: fstype, source and super options.
+	mountInfoTailFields = 3
+	escapeLength        = 3
+	optionalShared      = "shared:"
+	optionalMaster      = "master:"
+	optionalUnbindable  = "unbindable"
+
+	// PropagationPrivate is reported for mounts that neither send nor receive propagation events.
+	PropagationPrivate = "private"
+	// PropagationShared is reported for mounts in a peer group.
+	PropagationShared = "shared"
+	// PropagationSlave is reported for mounts receiving propagation events from a master peer group.
+	PropagationSlave = "slave"
+	// PropagationSharedSlave is reported for slave mounts that are also in a peer group.
+	PropagationSharedSlave = "shared,slave"
+	// PropagationUnbindable is reported for mounts that cannot be bind mounted.
+	PropagationUnbindable = "unbindable"
+)
+
+// Mount represents a single entry of the mount table. Only the mount point, filesystem type, propagation
+// and mount flags are measured; the device, root and source depend on disk naming and are only reported
+// as evidence.
+type Mount struct {
+	MountPoint   string   `yaml:"mountPoint"`
+	Device       string   `yaml:"-"`
+	Root         string   `yaml:"-"`
+	FSType       string   `yaml:"fsType"`
+	Source       string   `yaml:"-"`
+	Propagation  string   `yaml:"propagation"`
+	Flags        []string `yaml:"flags"`
+	SuperOptions []string `yaml:"-"`
+}
+
+// GetMounts parses the mount table of the host from /proc/1/mountinfo. The mounts are sorted by mount point,
+// keeping the mount order for stacked mounts, and carry no IDs so that the layout is canonical.
+func GetMounts() ([]Mount, error) {
+	file, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", mountInfoPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	mounts := make([]Mount, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		mount, ok := parseMountInfoLine(scanner.Text())
+		if ok {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", mountInfoPath, err)
+	}
+
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return mounts[i].MountPoint < mounts[j].MountPoint
+	})
+
+	return mounts, nil
+}
+
+// EffectiveMount returns the mount visible at the mount point. The last matching entry wins,
+// as later mounts shadow earlier ones.
+func EffectiveMount(mounts []Mount, mountPoint string) (Mount, bool) {
+	var (
+		effective Mount
+		found     bool
+	)
+
+	for _, mount := range mounts {
+		if mount.MountPoint == mountPoint {
+			effective = mount
+			found = true
+		}
+	}
+
+	return effective, found
+}
+
+// parseMountInfoLine parses a line of the format
+// "<id> <parent> <major:minor> <root> <mount point> <options> [optional...] - <fstype> <source> <super options>".
+func parseMountInfoLine(line string) (Mount, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < mountInfoMinFields {
+		return Mount{}, false
+	}
+
+	optional := fields[mountInfoOptionalStart:]
+
+	separator := slices.Index(optional, mountInfoSeparator)
+	if separator < 0 || len(optional) < separator+1+mountInfoTailFields {
+		return Mount{}, false
+	}
+
+	tail := optional[separator+1:]
+	optional = optional[:separator]
+
+	flags := strings.Split(fields[5], ",")
+	sort.Strings(flags)
+
+	return Mount{
+		MountPoint:   unescape(fields[4]),
+		Device:       fields[2],
+		Root:         unescape(fields[3]),
+		FSType:       tail[0],
+		Source:       unescape(tail[1]),
+		Propagation:  propagation(optional),
+		Flags:        flags,
+		SuperOptions: strings.Split(tail[2], ","),
+	}, true
+}
+
+// propagation returns the propagation type from the optional fields of a mountinfo line.
+func propagation(optional []string) string {
+	var shared, slave, unbindable bool
+
+	for _, field := range optional {
+		switch {
+		case strings.HasPrefix(field, optionalShared):
+			shared = true
+		case strings.HasPrefix(field, optionalMaster):
+			slave = true
+		case field == optionalUnbindable:
+			unbindable = true
+		}
+	}
+
+	switch {
+	case shared && slave:
+		return PropagationSharedSlave
+	case shared:
+		return PropagationShared
+	case slave:
+		return PropagationSlave
+	case unbindable:
+		return PropagationUnbindable
+	default:
+		return PropagationPrivate
+	}
+}
+
+// unescape decodes the octal escapes the kernel uses for spaces, tabs, newlines and backslashes in paths.
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+escapeLength < len(value) {
+			code, err := strconv.ParseUint(value[i+1:i+1+escapeLength], 8, 8)
+			if err == nil {
+				builder.WriteByte(byte(code))
+
+				i += escapeLength
+
+				continue
+			}
+		}
+
+		builder.WriteByte(value[i])
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,113 @@
+package mounts
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// hostMountPoints lists the mount points of the Talos host that are measured. Mounts below them, such as
+// the overlays and volumes of pods, come and go with the workloads and are only reported as evidence.
+//
+//nolint:gochecknoglobals
+var hostMountPoints = []string{
+	"/",
+	"/dev",
+	"/dev/pts",
+	"/dev/shm",
+	"/proc",
+	"/run",
+	"/sys",
+	"/sys/firmware/efi/efivars",
+	"/sys/fs/bpf",
+	"/sys/fs/cgroup",
+	"/sys/kernel/security",
+	"/system",
+	"/system/state",
+	"/tmp",
+	"/var",
+}
+
+// State represents the mount table and the result of the mount policy rules.
+type State struct {
+	Mounts []Mount             `yaml:"mounts"`
+	Rules  []utils.CheckResult `yaml:"rules"`
+}
+
+// Attestable implements the report.Attestable interface for the mount table.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "mounts"
+}
+
+// Measure returns the measurement of the canonical layout of the host mount points.
+func (a *Attestable) Measure() (string, error) {
+	mounts, err := GetMounts()
+	if err != nil {
+		return "", fmt.Errorf("failed to get mounts: %w", err)
+	}
+
+	data, err := yaml.Marshal(HostMounts(mounts))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mounts: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = &State{
+		Mounts: mounts,
+		Rules:  EvaluateRules(mounts),
+	}
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about every mount and the result of each mount policy rule.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"mounts_count":          strconv.Itoa(len(a.state.Mounts)),
+		"measured_mounts_count": strconv.Itoa(len(HostMounts(a.state.Mounts))),
+		"timestamp":             a.timestamp,
+	}
+
+	for i, mount := range a.state.Mounts {
+		prefix := fmt.Sprintf("mount_%d_", i)
+		evidence[prefix+"point"] = mount.MountPoint
+		evidence[prefix+"fstype"] = mount.FSType
+		evidence[prefix+"source"] = mount.Source
+		evidence[prefix+"propagation"] = mount.Propagation
+		evidence[prefix+"flags"] = strings.Join(mount.Flags, ",")
+	}
+
+	for _, rule := range a.state.Rules {
+		prefix := "rule_" + strings.ReplaceAll(rule.Name, "-", "_")
+		evidence[prefix] = rule.Result
+
+		if rule.Reason != "" {
+			evidence[prefix+"_reason"] = rule.Reason
+		}
+	}
+
+	return evidence, nil
+}
+
+// HostMounts returns the mounts of the host mount points, in the order of the mount table.
+func HostMounts(mounts []Mount) []Mount {
+	host := make([]Mount, 0, len(hostMountPoints))
+
+	for _, mount := range mounts {
+		if slices.Contains(hostMountPoints, mount.MountPoint) {
+			host = append(host, mount)
+		}
+	}
+
+	return host
+}
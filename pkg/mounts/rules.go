@@ -0,0 +1,66 @@
+package mounts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	flagReadOnly = "ro"
+)
+
+// mountRule requires the mount at a mount point to have the given filesystem type and mount flags.
+type mountRule struct {
+	name       string
+	mountPoint string
+	fsType     string
+	flags      []string
+}
+
+//nolint:gochecknoglobals
+var mountRules = []mountRule{
+	{name: "root-ro-squashfs", mountPoint: "/", fsType: "squashfs", flags: []string{flagReadOnly}},
+	{name: "tmp-nosuid-nodev", mountPoint: "/tmp", flags: []string{"nosuid", "nodev"}},
+	{name: "var-nosuid-nodev", mountPoint: "/var", flags: []string{"nosuid", "nodev"}},
+	{name: "dev-shm-nosuid-nodev-noexec", mountPoint: "/dev/shm", flags: []string{"nosuid", "nodev", "noexec"}},
+}
+
+// EvaluateRules evaluates the mount policy rules against the mount table. A rule is skipped when its mount
+// point is not mounted.
+func EvaluateRules(mounts []Mount) []utils.CheckResult {
+	rules := make([]utils.CheckResult, 0, len(mountRules))
+
+	for _, rule := range mountRules {
+		rules = append(rules, rule.evaluate(mounts))
+	}
+
+	return rules
+}
+
+func (r mountRule) evaluate(mounts []Mount) utils.CheckResult {
+	mount, ok := EffectiveMount(mounts, r.mountPoint)
+	if !ok {
+		return utils.Skip(r.name, r.mountPoint+" is not mounted")
+	}
+
+	problems := make([]string, 0)
+
+	if r.fsType != "" && mount.FSType != r.fsType {
+		problems = append(problems, fmt.Sprintf("filesystem is %s, expected %s", mount.FSType, r.fsType))
+	}
+
+	for _, flag := range r.flags {
+		if !slices.Contains(mount.Flags, flag) {
+			problems = append(problems, "missing "+flag)
+		}
+	}
+
+	if len(problems) > 0 {
+		return utils.Fail(r.name, r.mountPoint+": "+strings.Join(problems, ", "))
+	}
+
+	return utils.Pass(r.name)
+}
@@ -0,0 +1,9 @@
+// Package netlink provides error definitions for netlink operations.
+package netlink
+
+import "errors"
+
+var (
+	// ErrInvalidMessage is returned when a netlink message cannot be parsed.
+	ErrInvalidMessage = errors.New("invalid netlink message")
+)
@@ -0,0 +1,239 @@
+// Package netlink provides a minimal netlink client for the attestables that query kernel subsystems
+// over netlink, such as nftables and WireGuard.
+package netlink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	receiveBufferSize = 64 * 1024
+	uint16Size        = 2
+	uint32Size        = 4
+	attributeTypeMask = ^uint16(unix.NLA_F_NESTED | unix.NLA_F_NET_BYTEORDER)
+)
+
+// Attribute represents a netlink attribute with its payload. The payload of nested attributes holds
+// further attributes.
+type Attribute struct {
+	Type uint16
+	Data []byte
+}
+
+// Conn is a netlink connection to a kernel subsystem.
+type Conn struct {
+	fd  int
+	seq uint32
+}
+
+// Dial opens a netlink connection for the given netlink protocol, e.g. unix.NETLINK_NETFILTER.
+func Dial(protocol int) (*Conn, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, protocol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open netlink socket: %w", err)
+	}
+
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+	if err != nil {
+		_ = unix.Close(fd)
+
+		return nil, fmt.Errorf("failed to bind netlink socket: %w", err)
+	}
+
+	return &Conn{fd: fd}, nil
+}
+
+// Close closes the netlink connection.
+func (c *Conn) Close() {
+	_ = unix.Close(c.fd)
+}
+
+// Dump sends a dump request of the message type with the family specific header and the attributes,
+// and returns the payload of each message of the response, starting with the family specific header.
+func (c *Conn) Dump(msgType uint16, header []byte, attributes []Attribute) ([][]byte, error) {
+	c.seq++
+
+	err := unix.Sendto(c.fd, encodeRequest(msgType, c.seq, header, attributes), 0,
+		&unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send netlink request: %w", err)
+	}
+
+	payloads := make([][]byte, 0)
+	buffer := make([]byte, receiveBufferSize)
+
+	for {
+		n, _, err := unix.Recvfrom(c.fd, buffer, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive netlink response: %w", err)
+		}
+
+		messages, err := syscall.ParseNetlinkMessage(buffer[:n])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse netlink response: %w", err)
+		}
+
+		for _, message := range messages {
+			if message.Header.Seq != c.seq {
+				continue
+			}
+
+			switch message.Header.Type {
+			case unix.NLMSG_DONE:
+				return payloads, nil
+			case unix.NLMSG_ERROR:
+				err := parseError(message.Data)
+				if err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			payloads = append(payloads, message.Data)
+		}
+	}
+}
+
+// ParseAttributes parses the attributes in the payload.
+func ParseAttributes(data []byte) ([]Attribute, error) {
+	attributes := make([]Attribute, 0)
+
+	for len(data) >= unix.SizeofNlAttr {
+		length := int(binary.NativeEndian.Uint16(data[0:2]))
+		kind := binary.NativeEndian.Uint16(data[2:4])
+
+		if length < unix.SizeofNlAttr || length > len(data) {
+			return nil, fmt.Errorf("%w: attribute length %d", ErrInvalidMessage, length)
+		}
+
+		attributes = append(attributes, Attribute{
+			Type: kind & attributeTypeMask,
+			Data: data[unix.SizeofNlAttr:length],
+		})
+
+		if align(length) >= len(data) {
+			break
+		}
+
+		data = data[align(length):]
+	}
+
+	return attributes, nil
+}
+
+// Find returns the payload of the first attribute of the type.
+func Find(attributes []Attribute, kind uint16) ([]byte, bool) {
+	for _, attr := range attributes {
+		if attr.Type == kind {
+			return attr.Data, true
+		}
+	}
+
+	return nil, false
+}
+
+// FindString returns the value of a NUL-terminated string attribute.
+func FindString(attributes []Attribute, kind uint16) string {
+	data, ok := Find(attributes, kind)
+	if !ok {
+		return ""
+	}
+
+	return unix.ByteSliceToString(data)
+}
+
+// FindUint16 returns the value of a 16-bit attribute in the given byte order.
+func FindUint16(attributes []Attribute, kind uint16, order binary.ByteOrder) (uint16, bool) {
+	data, ok := Find(attributes, kind)
+	if !ok || len(data) < uint16Size {
+		return 0, false
+	}
+
+	return order.Uint16(data), true
+}
+
+// FindUint32 returns the value of a 32-bit attribute in the given byte order.
+func FindUint32(attributes []Attribute, kind uint16, order binary.ByteOrder) (uint32, bool) {
+	data, ok := Find(attributes, kind)
+	if !ok || len(data) < uint32Size {
+		return 0, false
+	}
+
+	return order.Uint32(data), true
+}
+
+// FindNested returns the attributes nested in the first attribute of the type.
+func FindNested(attributes []Attribute, kind uint16) ([]Attribute, error) {
+	data, ok := Find(attributes, kind)
+	if !ok {
+		return []Attribute{}, nil
+	}
+
+	return ParseAttributes(data)
+}
+
+// StringAttribute returns a NUL-terminated string attribute.
+func StringAttribute(kind uint16, value string) Attribute {
+	return Attribute{Type: kind, Data: append([]byte(value), 0)}
+}
+
+// IsUnavailable reports whether the error means the subsystem is not available to the caller,
+// because it is not built into the kernel or the caller lacks the privileges to query it.
+func IsUnavailable(err error) bool {
+	return errors.Is(err, unix.EPERM) || errors.Is(err, unix.EACCES) ||
+		errors.Is(err, unix.EPROTONOSUPPORT) || errors.Is(err, unix.EAFNOSUPPORT)
+}
+
+func encodeRequest(msgType uint16, seq uint32, header []byte, attributes []Attribute) []byte {
+	payload := append([]byte{}, header...)
+	for _, attr := range attributes {
+		payload = append(payload, encodeAttribute(attr)...)
+	}
+
+	length := unix.SizeofNlMsghdr + len(payload)
+	request := make([]byte, unix.SizeofNlMsghdr, length)
+
+	//nolint:gosec // Requests are small
+	binary.NativeEndian.PutUint32(request[0:4], uint32(length))
+	binary.NativeEndian.PutUint16(request[4:6], msgType)
+	binary.NativeEndian.PutUint16(request[6:8], unix.NLM_F_REQUEST|unix.NLM_F_DUMP)
+	binary.NativeEndian.PutUint32(request[8:12], seq)
+
+	return append(request, payload...)
+}
+
+func encodeAttribute(attr Attribute) []byte {
+	length := unix.SizeofNlAttr + len(attr.Data)
+	encoded := make([]byte, align(length))
+
+	//nolint:gosec // Attributes are small
+	binary.NativeEndian.PutUint16(encoded[0:2], uint16(length))
+	binary.NativeEndian.PutUint16(encoded[2:4], attr.Type)
+	copy(encoded[unix.SizeofNlAttr:], attr.Data)
+
+	return encoded
+}
+
+func parseError(data []byte) error {
+	if len(data) < unix.SizeofNlMsgerr {
+		return fmt.Errorf("%w: truncated netlink error", ErrInvalidMessage)
+	}
+
+	errno := int32(binary.NativeEndian.Uint32(data[0:4])) //nolint:gosec // The error code is a signed 32-bit value
+	if errno == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("netlink request failed: %w", os.NewSyscallError("netlink", unix.Errno(-errno)))
+}
+
+func align(length int) int {
+	return (length + unix.NLA_ALIGNTO - 1) &^ (unix.NLA_ALIGNTO - 1)
+}
@@ -0,0 +1,135 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	resolvConfPath   = "/etc/resolv.conf"
+	hostsPath        = "/etc/hosts"
+	caCertsDir       = "/etc/ssl/certs"
+	nameserverPrefix = "nameserver"
+	certificateBegin = "-----BEGIN CERTIFICATE-----"
+)
+
+//nolint:gochecknoglobals
+var configFiles = []string{
+	resolvConfPath,
+	hostsPath,
+}
+
+// CABundle represents the digest of the CA certificates trusted by the machine.
+type CABundle struct {
+	Present      bool   `yaml:"present"`
+	Hash         string `yaml:"hash"`
+	Files        int    `yaml:"files"`
+	Certificates int    `yaml:"certificates"`
+}
+
+// GetCABundle hashes the files of the CA certificate directory in name order, following symlinks,
+// and counts the PEM certificates they contain. A missing directory is reported as not present.
+func GetCABundle() (*CABundle, error) {
+	entries, err := os.ReadDir(caCertsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &CABundle{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", caCertsDir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	var digest bytes.Buffer
+
+	bundle := &CABundle{Present: true}
+
+	for _, name := range names {
+		path := filepath.Join(caCertsDir, name)
+
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		//nolint:gosec // CA directory path is controlled
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		digest.WriteString(name + ":" + utils.EncodeMeasurement(data) + "\n")
+
+		bundle.Files++
+		bundle.Certificates += bytes.Count(data, []byte(certificateBegin))
+	}
+
+	bundle.Hash = utils.EncodeMeasurement(digest.Bytes())
+
+	return bundle, nil
+}
+
+// hashFiles returns the hash of each file. Missing files are reported with an empty hash.
+func hashFiles(paths []string) ([]File, error) {
+	files := make([]File, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			files = append(files, File{Path: path})
+
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		files = append(files, File{
+			Path: path,
+			Hash: utils.EncodeMeasurement(data),
+		})
+	}
+
+	return files, nil
+}
+
+func getNameservers() ([]string, error) {
+	file, err := os.Open(resolvConfPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", resolvConfPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	nameservers := make([]string, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == nameserverPrefix {
+			nameservers = append(nameservers, fields[1])
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", resolvConfPath, err)
+	}
+
+	return nameservers, nil
+}
@@ -0,0 +1,184 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	sysClassNetPath      = "/sys/class/net"
+	procRoutePath        = "/proc/net/route"
+	procIPv6RoutePath    = "/proc/net/ipv6_route"
+	routeFields          = 8
+	routeInterfaceField  = 0
+	routeDestField       = 1
+	routeGatewayField    = 2
+	routeMetricField     = 6
+	ipv6RouteFields      = 10
+	ipv6RouteDestField   = 0
+	ipv6RoutePrefixField = 1
+	ipv6RouteGateway     = 4
+	ipv6RouteMetricField = 5
+	ipv6RouteIfaceField  = 9
+	defaultDestination   = "00000000"
+	defaultIPv6Dest      = "00000000000000000000000000000000"
+	defaultIPv6Prefix    = "00"
+)
+
+// GetInterfaces returns the network interfaces sorted by name with their addresses, link state and driver.
+func GetInterfaces() ([]Interface, error) {
+	links, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	interfaces := make([]Interface, 0, len(links))
+
+	for _, link := range links {
+		addrs, err := link.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get addresses of %s: %w", link.Name, err)
+		}
+
+		addresses := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			addresses = append(addresses, addr.String())
+		}
+
+		sort.Strings(addresses)
+
+		interfaces = append(interfaces, Interface{
+			Name:      link.Name,
+			MAC:       link.HardwareAddr.String(),
+			Driver:    utils.GetDriver(filepath.Join(sysClassNetPath, link.Name, "device")),
+			Addresses: addresses,
+			State:     utils.ReadSysfsValue(filepath.Join(sysClassNetPath, link.Name, "operstate")),
+		})
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Name < interfaces[j].Name
+	})
+
+	return interfaces, nil
+}
+
+// GetDefaultRoutes returns the IPv4 and IPv6 default routes of the main routing table.
+func GetDefaultRoutes() ([]Route, error) {
+	routes, err := readRouteTable(procRoutePath, routeFields, parseIPv4Route)
+	if err != nil {
+		return nil, err
+	}
+
+	ipv6Routes, err := readRouteTable(procIPv6RoutePath, ipv6RouteFields, parseIPv6Route)
+	if err != nil {
+		return nil, err
+	}
+
+	routes = append(routes, ipv6Routes...)
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Interface != routes[j].Interface {
+			return routes[i].Interface < routes[j].Interface
+		}
+
+		return routes[i].Gateway < routes[j].Gateway
+	})
+
+	return routes, nil
+}
+
+func readRouteTable(path string, minFields int, parse func(fields []string) (Route, bool)) ([]Route, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Route{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	routes := make([]Route, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < minFields {
+			continue
+		}
+
+		route, ok := parse(fields)
+		if ok {
+			routes = append(routes, route)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+
+	return routes, nil
+}
+
+// parseIPv4Route parses a line of /proc/net/route, where addresses are native endian hex.
+func parseIPv4Route(fields []string) (Route, bool) {
+	if fields[routeDestField] != defaultDestination {
+		return Route{}, false
+	}
+
+	raw, err := hex.DecodeString(fields[routeGatewayField])
+	if err != nil || len(raw) != net.IPv4len {
+		return Route{}, false
+	}
+
+	gateway := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(gateway, binary.NativeEndian.Uint32(raw))
+
+	return Route{
+		Interface: fields[routeInterfaceField],
+		Gateway:   gateway.String(),
+		Metric:    fields[routeMetricField],
+	}, true
+}
+
+// parseIPv6Route parses a line of /proc/net/ipv6_route, where addresses are big endian hex.
+func parseIPv6Route(fields []string) (Route, bool) {
+	if fields[ipv6RouteDestField] != defaultIPv6Dest || fields[ipv6RoutePrefixField] != defaultIPv6Prefix {
+		return Route{}, false
+	}
+
+	// The loopback interface carries the unreachable default route of the IPv6 routing table.
+	if fields[ipv6RouteIfaceField] == "lo" {
+		return Route{}, false
+	}
+
+	raw, err := hex.DecodeString(fields[ipv6RouteGateway])
+	if err != nil || len(raw) != net.IPv6len {
+		return Route{}, false
+	}
+
+	metric, err := strconv.ParseUint(fields[ipv6RouteMetricField], 16, 32)
+	if err != nil {
+		return Route{}, false
+	}
+
+	return Route{
+		Interface: fields[ipv6RouteIfaceField],
+		Gateway:   net.IP(raw).String(),
+		Metric:    strconv.FormatUint(metric, 10),
+	}, true
+}
@@ -0,0 +1,167 @@
+// Package network provides utilities to attest the network identity and resolver configuration of the machine.
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// Interface represents a network interface of the machine. The addresses include DHCP leases and temporary
+// IPv6 addresses which rotate on their own, so they are only reported as evidence, as is the link state.
+type Interface struct {
+	Name      string   `yaml:"name"`
+	MAC       string   `yaml:"mac"`
+	Driver    string   `yaml:"driver"`
+	Addresses []string `yaml:"-"`
+	State     string   `yaml:"-"`
+}
+
+// Route represents a default route.
+type Route struct {
+	Interface string `yaml:"interface"`
+	Gateway   string `yaml:"gateway"`
+	Metric    string `yaml:"metric"`
+}
+
+// File represents the hash of a configuration file relevant to the network trust of the machine.
+type File struct {
+	Path string `yaml:"path"`
+	Hash string `yaml:"hash"`
+}
+
+// State represents the network identity and resolver configuration of the machine.
+type State struct {
+	Interfaces    []Interface `yaml:"interfaces"`
+	DefaultRoutes []Route     `yaml:"defaultRoutes"`
+	Files         []File      `yaml:"files"`
+	CABundle      CABundle    `yaml:"caBundle"`
+	Nameservers   []string    `yaml:"nameservers"`
+}
+
+// Attestable implements the report.Attestable interface for the network identity and resolver configuration.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "network"
+}
+
+// Measure returns the measurement of the physical interfaces, the default routes, the resolver
+// configuration and the CA bundle. Virtual interfaces, such as the veth pairs of pods, come and go
+// with the workloads and are only reported as evidence.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetNetworkState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get network state: %w", err)
+	}
+
+	measured := *state
+	measured.Interfaces = physicalInterfaces(state.Interfaces)
+
+	data, err := yaml.Marshal(measured)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal network state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about each interface, the default routes, the resolver configuration and the CA bundle.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"interfaces_count":     strconv.Itoa(len(a.state.Interfaces)),
+		"default_routes_count": strconv.Itoa(len(a.state.DefaultRoutes)),
+		"nameservers":          strings.Join(a.state.Nameservers, ","),
+		"ca_bundle_present":    strconv.FormatBool(a.state.CABundle.Present),
+		"ca_bundle_hash":       a.state.CABundle.Hash,
+		"ca_bundle_files":      strconv.Itoa(a.state.CABundle.Files),
+		"ca_certificates":      strconv.Itoa(a.state.CABundle.Certificates),
+		"timestamp":            a.timestamp,
+	}
+
+	for _, iface := range a.state.Interfaces {
+		prefix := "interface_" + iface.Name + "_"
+		evidence[prefix+"mac"] = iface.MAC
+		evidence[prefix+"driver"] = iface.Driver
+		evidence[prefix+"state"] = iface.State
+		evidence[prefix+"addresses"] = strings.Join(iface.Addresses, ",")
+	}
+
+	for i, route := range a.state.DefaultRoutes {
+		prefix := fmt.Sprintf("default_route_%d_", i)
+		evidence[prefix+"interface"] = route.Interface
+		evidence[prefix+"gateway"] = route.Gateway
+		evidence[prefix+"metric"] = route.Metric
+	}
+
+	for _, file := range a.state.Files {
+		evidence[fileEvidenceKey(file.Path)] = file.Hash
+	}
+
+	return evidence, nil
+}
+
+// GetNetworkState reads the network interfaces, the default routes, the resolver configuration and the CA bundle.
+func GetNetworkState() (*State, error) {
+	interfaces, err := GetInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := GetDefaultRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := hashFiles(configFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := GetCABundle()
+	if err != nil {
+		return nil, err
+	}
+
+	nameservers, err := getNameservers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Interfaces:    interfaces,
+		DefaultRoutes: routes,
+		Files:         files,
+		CABundle:      *bundle,
+		Nameservers:   nameservers,
+	}, nil
+}
+
+func physicalInterfaces(interfaces []Interface) []Interface {
+	physical := make([]Interface, 0, len(interfaces))
+
+	for _, iface := range interfaces {
+		if iface.Driver != "" {
+			physical = append(physical, iface)
+		}
+	}
+
+	return physical
+}
+
+// fileEvidenceKey returns the evidence key of a file hash, e.g. "resolv_conf_hash" for /etc/resolv.conf.
+func fileEvidenceKey(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+
+	return strings.NewReplacer(".", "_", "-", "_").Replace(name) + "_hash"
+}
@@ -0,0 +1,72 @@
+package nftables
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	nfgenmsgSize = 4
+)
+
+// message represents a single nftables object from a netlink dump.
+type message struct {
+	family     uint8
+	attributes []netlink.Attribute
+}
+
+// conn is a netlink connection to the nftables subsystem of nfnetlink.
+type conn struct {
+	*netlink.Conn
+}
+
+func dial() (*conn, error) {
+	c, err := netlink.Dial(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nfnetlink: %w", err)
+	}
+
+	return &conn{Conn: c}, nil
+}
+
+// dump requests all objects of the given nftables message type in the family, filtered by the attributes.
+func (c *conn) dump(msgType uint16, family uint8, attributes []netlink.Attribute) ([]message, error) {
+	header := []byte{family, unix.NFNETLINK_V0, 0, 0}
+
+	payloads, err := c.Dump(unix.NFNL_SUBSYS_NFTABLES<<8|msgType, header, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump nftables objects: %w", err)
+	}
+
+	messages := make([]message, 0, len(payloads))
+
+	for _, payload := range payloads {
+		if len(payload) < nfgenmsgSize {
+			return nil, fmt.Errorf("%w: truncated nfgenmsg", netlink.ErrInvalidMessage)
+		}
+
+		attributes, err := netlink.ParseAttributes(payload[nfgenmsgSize:])
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message{
+			family:     payload[0],
+			attributes: attributes,
+		})
+	}
+
+	return messages, nil
+}
+
+func findString(attributes []netlink.Attribute, kind uint16) string {
+	return netlink.FindString(attributes, kind)
+}
+
+// findUint32 returns the value of a 32-bit attribute, which nftables encodes in network byte order.
+func findUint32(attributes []netlink.Attribute, kind uint16) (uint32, bool) {
+	return netlink.FindUint32(attributes, kind, binary.BigEndian)
+}
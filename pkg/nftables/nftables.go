@@ -0,0 +1,477 @@
+// Package nftables provides utilities to attest the nftables ruleset of the host firewall over netlink.
+package nftables
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/netlink"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	familyUnspec     = unix.NFPROTO_UNSPEC
+	familyNetdev     = unix.NFPROTO_NETDEV
+	familyARP        = unix.NFPROTO_ARP
+	hookInput        = "input"
+	policyDrop       = "drop"
+	setElemKeyEnd    = 10 // NFTA_SET_ELEM_KEY_END
+	verdictDrop      = 0  // NF_DROP
+	verdictAccept    = 1  // NF_ACCEPT
+	expressionSep    = ":"
+	expressionNoData = ""
+)
+
+//nolint:gochecknoglobals
+var families = map[uint8]string{
+	unix.NFPROTO_INET:   "inet",
+	unix.NFPROTO_IPV4:   "ip",
+	unix.NFPROTO_ARP:    "arp",
+	unix.NFPROTO_NETDEV: "netdev",
+	unix.NFPROTO_BRIDGE: "bridge",
+	unix.NFPROTO_IPV6:   "ip6",
+}
+
+//nolint:gochecknoglobals
+var (
+	inetHooks   = []string{"prerouting", "input", "forward", "output", "postrouting", "ingress"}
+	arpHooks    = []string{"input", "output", "forward"}
+	netdevHooks = []string{"ingress", "egress"}
+)
+
+// statefulExpressions lists the expressions whose data changes as packets are processed, e.g. counters.
+// Their data is left out of the canonical ruleset.
+//
+//nolint:gochecknoglobals
+var statefulExpressions = map[string]bool{
+	"counter": true,
+	"quota":   true,
+	"last":    true,
+}
+
+// Table represents an nftables table with its chains and sets.
+type Table struct {
+	Family string  `yaml:"family"`
+	Name   string  `yaml:"name"`
+	Flags  uint32  `yaml:"flags"`
+	Chains []Chain `yaml:"chains"`
+	Sets   []Set   `yaml:"sets"`
+}
+
+// Chain represents an nftables chain. Base chains carry their type, hook, priority and policy.
+type Chain struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type,omitempty"`
+	Hook     string `yaml:"hook,omitempty"`
+	Priority int32  `yaml:"priority"`
+	Policy   string `yaml:"policy,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+// Rule represents an nftables rule as the ordered list of its canonical expressions.
+type Rule struct {
+	Expressions []string `yaml:"expressions"`
+}
+
+// Set represents an nftables set and its elements.
+type Set struct {
+	Name     string   `yaml:"name"`
+	Flags    uint32   `yaml:"flags"`
+	Elements []string `yaml:"elements"`
+}
+
+// State represents the canonical nftables ruleset.
+type State struct {
+	Available bool    `yaml:"available"`
+	Tables    []Table `yaml:"tables"`
+}
+
+// Attestable implements the report.Attestable interface for the nftables ruleset.
+type Attestable struct {
+	state     *State
+	digest    string
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "nftables"
+}
+
+// Measure returns the measurement of the canonical nftables ruleset.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetRuleset()
+	if err != nil {
+		return "", fmt.Errorf("failed to get nftables ruleset: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal nftables ruleset: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+	a.digest = utils.EncodeMeasurement(data)
+
+	return a.digest, nil
+}
+
+// Evidence returns metadata about the nftables tables, chains, rules and default policies.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"available":      strconv.FormatBool(a.state.Available),
+		"ruleset_digest": a.digest,
+		"tables_count":   strconv.Itoa(len(a.state.Tables)),
+		"timestamp":      a.timestamp,
+	}
+
+	var chains, rules, sets int
+
+	inputDrop := false
+
+	for _, table := range a.state.Tables {
+		chains += len(table.Chains)
+		sets += len(table.Sets)
+
+		for _, chain := range table.Chains {
+			rules += len(chain.Rules)
+
+			if chain.Hook == "" {
+				continue
+			}
+
+			prefix := fmt.Sprintf("chain_%s_%s_%s_", table.Family, table.Name, chain.Name)
+			evidence[prefix+"hook"] = chain.Hook
+			evidence[prefix+"priority"] = strconv.Itoa(int(chain.Priority))
+			evidence[prefix+"policy"] = chain.Policy
+			evidence[prefix+"rules_count"] = strconv.Itoa(len(chain.Rules))
+
+			if chain.Hook == hookInput && chain.Policy == policyDrop {
+				inputDrop = true
+			}
+		}
+	}
+
+	evidence["chains_count"] = strconv.Itoa(chains)
+	evidence["rules_count"] = strconv.Itoa(rules)
+	evidence["sets_count"] = strconv.Itoa(sets)
+	evidence["input_default_drop"] = strconv.FormatBool(inputDrop)
+
+	return evidence, nil
+}
+
+// GetRuleset dumps the nftables ruleset over netlink and canonicalizes it: tables are sorted by family and name,
+// chains and sets by name, rules keep their evaluation order, and handles and packet counters are left out.
+// When nftables is not available to the extension, the ruleset is reported as unavailable.
+func GetRuleset() (*State, error) {
+	state := &State{
+		Tables: make([]Table, 0),
+	}
+
+	c, err := dial()
+	if netlink.IsUnavailable(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer c.Close()
+
+	tables, err := c.dump(unix.NFT_MSG_GETTABLE, familyUnspec, nil)
+	if netlink.IsUnavailable(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	state.Available = true
+
+	index := make(map[string]*Table)
+
+	for _, msg := range tables {
+		flags, _ := findUint32(msg.attributes, unix.NFTA_TABLE_FLAGS)
+
+		state.Tables = append(state.Tables, Table{
+			Family: familyName(msg.family),
+			Name:   findString(msg.attributes, unix.NFTA_TABLE_NAME),
+			Flags:  flags,
+			Chains: make([]Chain, 0),
+			Sets:   make([]Set, 0),
+		})
+	}
+
+	for i := range state.Tables {
+		index[state.Tables[i].Family+"/"+state.Tables[i].Name] = &state.Tables[i]
+	}
+
+	err = c.addChains(index)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.addSets(index)
+	if err != nil {
+		return nil, err
+	}
+
+	sortTables(state.Tables)
+
+	return state, nil
+}
+
+func (c *conn) addChains(index map[string]*Table) error {
+	chains, err := c.dump(unix.NFT_MSG_GETCHAIN, familyUnspec, nil)
+	if err != nil {
+		return err
+	}
+
+	chainIndex := make(map[string]*Chain)
+
+	for _, msg := range chains {
+		chain, err := parseChain(msg)
+		if err != nil {
+			return err
+		}
+
+		key := familyName(msg.family) + "/" + findString(msg.attributes, unix.NFTA_CHAIN_TABLE)
+
+		table, ok := index[key]
+		if ok {
+			table.Chains = append(table.Chains, chain)
+		}
+	}
+
+	for key, table := range index {
+		for i := range table.Chains {
+			chainIndex[key+"/"+table.Chains[i].Name] = &table.Chains[i]
+		}
+	}
+
+	rules, err := c.dump(unix.NFT_MSG_GETRULE, familyUnspec, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range rules {
+		rule, err := parseRule(msg)
+		if err != nil {
+			return err
+		}
+
+		key := familyName(msg.family) + "/" + findString(msg.attributes, unix.NFTA_RULE_TABLE) + "/" +
+			findString(msg.attributes, unix.NFTA_RULE_CHAIN)
+
+		chain, ok := chainIndex[key]
+		if ok {
+			chain.Rules = append(chain.Rules, rule)
+		}
+	}
+
+	return nil
+}
+
+func (c *conn) addSets(index map[string]*Table) error {
+	sets, err := c.dump(unix.NFT_MSG_GETSET, familyUnspec, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range sets {
+		tableName := findString(msg.attributes, unix.NFTA_SET_TABLE)
+
+		table, ok := index[familyName(msg.family)+"/"+tableName]
+		if !ok {
+			continue
+		}
+
+		flags, _ := findUint32(msg.attributes, unix.NFTA_SET_FLAGS)
+		set := Set{
+			Name:  findString(msg.attributes, unix.NFTA_SET_NAME),
+			Flags: flags,
+		}
+
+		set.Elements, err = c.getSetElements(msg.family, tableName, set.Name)
+		if err != nil {
+			return err
+		}
+
+		table.Sets = append(table.Sets, set)
+	}
+
+	return nil
+}
+
+// getSetElements returns the sorted canonical elements of the set, leaving out timeouts and per-element counters.
+func (c *conn) getSetElements(family uint8, table string, set string) ([]string, error) {
+	messages, err := c.dump(unix.NFT_MSG_GETSETELEM, family, []netlink.Attribute{
+		netlink.StringAttribute(unix.NFTA_SET_ELEM_LIST_TABLE, table),
+		netlink.StringAttribute(unix.NFTA_SET_ELEM_LIST_SET, set),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump elements of set %s: %w", set, err)
+	}
+
+	elements := make([]string, 0)
+
+	for _, msg := range messages {
+		list, err := netlink.FindNested(msg.attributes, unix.NFTA_SET_ELEM_LIST_ELEMENTS)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list {
+			if item.Type != unix.NFTA_LIST_ELEM {
+				continue
+			}
+
+			attributes, err := netlink.ParseAttributes(item.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			var element strings.Builder
+
+			for _, attr := range attributes {
+				switch attr.Type {
+				case unix.NFTA_SET_ELEM_KEY, setElemKeyEnd, unix.NFTA_SET_ELEM_DATA, unix.NFTA_SET_ELEM_FLAGS:
+					element.WriteString(strconv.Itoa(int(attr.Type)) + expressionSep + hex.EncodeToString(attr.Data) + ";")
+				}
+			}
+
+			elements = append(elements, element.String())
+		}
+	}
+
+	sort.Strings(elements)
+
+	return elements, nil
+}
+
+func parseChain(msg message) (Chain, error) {
+	chain := Chain{
+		Name:  findString(msg.attributes, unix.NFTA_CHAIN_NAME),
+		Type:  findString(msg.attributes, unix.NFTA_CHAIN_TYPE),
+		Rules: make([]Rule, 0),
+	}
+
+	hook, err := netlink.FindNested(msg.attributes, unix.NFTA_CHAIN_HOOK)
+	if err != nil {
+		return chain, err
+	}
+
+	number, ok := findUint32(hook, unix.NFTA_HOOK_HOOKNUM)
+	if !ok {
+		return chain, nil
+	}
+
+	priority, _ := findUint32(hook, unix.NFTA_HOOK_PRIORITY)
+
+	chain.Hook = hookName(msg.family, number)
+	chain.Priority = int32(priority) //nolint:gosec // The priority is a signed 32-bit value
+
+	policy, ok := findUint32(msg.attributes, unix.NFTA_CHAIN_POLICY)
+	if ok {
+		chain.Policy = verdictName(policy)
+	}
+
+	return chain, nil
+}
+
+// parseRule returns the canonical expressions of the rule as "<name>:<data>", leaving out the data
+// of stateful expressions.
+func parseRule(msg message) (Rule, error) {
+	rule := Rule{
+		Expressions: make([]string, 0),
+	}
+
+	list, err := netlink.FindNested(msg.attributes, unix.NFTA_RULE_EXPRESSIONS)
+	if err != nil {
+		return rule, err
+	}
+
+	for _, item := range list {
+		if item.Type != unix.NFTA_LIST_ELEM {
+			continue
+		}
+
+		attributes, err := netlink.ParseAttributes(item.Data)
+		if err != nil {
+			return rule, err
+		}
+
+		name := findString(attributes, unix.NFTA_EXPR_NAME)
+		data := expressionNoData
+
+		if !statefulExpressions[name] {
+			raw, _ := netlink.Find(attributes, unix.NFTA_EXPR_DATA)
+			data = hex.EncodeToString(raw)
+		}
+
+		rule.Expressions = append(rule.Expressions, name+expressionSep+data)
+	}
+
+	return rule, nil
+}
+
+func sortTables(tables []Table) {
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Family != tables[j].Family {
+			return tables[i].Family < tables[j].Family
+		}
+
+		return tables[i].Name < tables[j].Name
+	})
+
+	for _, table := range tables {
+		sort.Slice(table.Chains, func(i, j int) bool {
+			return table.Chains[i].Name < table.Chains[j].Name
+		})
+
+		sort.Slice(table.Sets, func(i, j int) bool {
+			return table.Sets[i].Name < table.Sets[j].Name
+		})
+	}
+}
+
+func familyName(family uint8) string {
+	name, ok := families[family]
+	if !ok {
+		return strconv.Itoa(int(family))
+	}
+
+	return name
+}
+
+func hookName(family uint8, number uint32) string {
+	hooks := inetHooks
+
+	switch family {
+	case familyARP:
+		hooks = arpHooks
+	case familyNetdev:
+		hooks = netdevHooks
+	}
+
+	if int(number) < len(hooks) {
+		return hooks[number]
+	}
+
+	return strconv.FormatUint(uint64(number), 10)
+}
+
+func verdictName(verdict uint32) string {
+	switch verdict {
+	case verdictDrop:
+		return policyDrop
+	case verdictAccept:
+		return "accept"
+	default:
+		return strconv.FormatUint(uint64(verdict), 10)
+	}
+}
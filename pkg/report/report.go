@@ -6,17 +6,39 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/acpi"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/apparmor"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/devices"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/ebpf"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/encryption"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/esrt"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/exposure"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/extensions"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/hsi"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/hwsecurity"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/ima"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/image"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/kernel"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/keyrings"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/lockdown"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/lsm"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/mounts"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/network"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/nftables"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/openapi/attestation/attestationmodels"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/secureboot"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/selinux"
-	"github.com/kommodity-io/kommodity-attestation-extension/pkg/squashfs"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/smbios"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/sockets"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/taint"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/timesync"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/tpm"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/uki"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/verity"
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/version"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/vulnerabilities"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/wireguard"
 )
 
 const (
@@ -37,18 +59,46 @@ type AttestableReport struct {
 	Attestables []Attestable
 }
 
+// Config holds the machine specific settings of the attestable components.
+type Config struct {
+	// UKIPath is the path of the booted UKI on the host, for when it cannot be found on the EFI system partition.
+	UKIPath string
+}
+
 // NewAllAttestableReport creates a new AttestableReport with all available attestable components.
-func NewAllAttestableReport() *AttestableReport {
+func NewAllAttestableReport(config Config) *AttestableReport {
 	return &AttestableReport{
 		Attestables: []Attestable{
+			&acpi.Attestable{},
 			&apparmor.Attestable{},
+			&devices.Attestable{},
+			&ebpf.Attestable{},
+			&encryption.Attestable{},
+			&esrt.Attestable{},
+			&exposure.Attestable{},
 			&extensions.Attestable{},
+			&hsi.Attestable{},
+			&hwsecurity.Attestable{},
+			&ima.Attestable{},
 			&image.Attestable{},
+			&kernel.Attestable{},
+			&keyrings.Attestable{},
 			&lockdown.Attestable{},
+			&lsm.Attestable{},
+			&mounts.Attestable{},
+			&network.Attestable{},
+			&nftables.Attestable{},
 			&secureboot.Attestable{},
 			&selinux.Attestable{},
-			&squashfs.Attestable{},
+			&smbios.Attestable{},
+			&sockets.Attestable{},
+			&taint.Attestable{},
+			&timesync.Attestable{},
+			&uki.Attestable{Path: config.UKIPath},
+			&verity.Attestable{},
 			&version.Attestable{},
+			&vulnerabilities.Attestable{},
+			&wireguard.Attestable{},
 		},
 	}
 }
@@ -0,0 +1,9 @@
+// Package selinux provides error definitions for SELinux operations.
+package selinux
+
+import "errors"
+
+var (
+	// ErrUnknownEnforceValue is returned when the SELinux enforce file holds an unexpected value.
+	ErrUnknownEnforceValue = errors.New("unknown SELinux enforce value")
+)
@@ -2,20 +2,59 @@
 package selinux
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
 )
 
 const (
-	seLinuxEnabledPath = "/sys/fs/selinux/enforce"
+	seLinuxFSPath           = "/sys/fs/selinux"
+	seLinuxEnabledPath      = seLinuxFSPath + "/enforce"
+	seLinuxPolicyPath       = seLinuxFSPath + "/policy"
+	seLinuxPolicyVersPath   = seLinuxFSPath + "/policyvers"
+	seLinuxMLSPath          = seLinuxFSPath + "/mls"
+	seLinuxDenyUnknownPath  = seLinuxFSPath + "/deny_unknown"
+	seLinuxCheckReqProtPath = seLinuxFSPath + "/checkreqprot"
+	seLinuxBooleansDir      = seLinuxFSPath + "/booleans"
+
+	// ModeEnforcing is reported when SELinux is enforcing its policy.
+	ModeEnforcing = "Enforcing"
+	// ModePermissive is reported when SELinux only logs policy violations.
+	ModePermissive = "Permissive"
+	// ModeAbsent is reported when SELinux is not present in the kernel or selinuxfs is not mounted.
+	ModeAbsent = "Absent"
+	// ModeError is reported when SELinux is present but its state could not be read.
+	ModeError = "Error"
 )
 
+// Boolean represents an SELinux policy boolean and its current value.
+type Boolean struct {
+	Name  string `yaml:"name"`
+	Value bool   `yaml:"value"`
+}
+
+// State represents the SELinux mode and the loaded policy.
+type State struct {
+	Mode          string    `yaml:"mode"`
+	Error         string    `yaml:"error,omitempty"`
+	PolicyVersion string    `yaml:"policyVersion"`
+	PolicyHash    string    `yaml:"policyHash"`
+	MLS           bool      `yaml:"mls"`
+	DenyUnknown   bool      `yaml:"denyUnknown"`
+	CheckReqProt  bool      `yaml:"checkReqProt"`
+	Booleans      []Boolean `yaml:"booleans"`
+}
+
 // Attestable implements the report.Attestable interface for SELinux.
 type Attestable struct {
-	mode      string
+	state     *State
 	timestamp string
 }
 
@@ -24,35 +63,164 @@ func (a *Attestable) Name() string {
 	return "selinux"
 }
 
-// Measure returns the measurement of the SELinux status.
+// Measure returns the measurement of the SELinux status and the loaded policy.
 func (a *Attestable) Measure() (string, error) {
-	mode, err := GetSELinuxMode()
+	state := GetSELinuxState()
+
+	data, err := yaml.Marshal(state)
 	if err != nil {
-		return "", fmt.Errorf("failed to determine SELinux status: %w", err)
+		return "", fmt.Errorf("failed to marshal SELinux state: %w", err)
 	}
 
 	a.timestamp = utils.UnixNowString()
-	a.mode = mode
+	a.state = state
 
-	return utils.EncodeMeasurement([]byte(mode)), nil
+	return utils.EncodeMeasurement(data), nil
 }
 
-// Evidence returns metadata about the SELinux status.
+// Evidence returns metadata about the SELinux status, the loaded policy and its booleans.
 func (a *Attestable) Evidence() (map[string]string, error) {
-	return map[string]string{
-		a.Name():    a.mode,
-		"timestamp": a.timestamp,
-	}, nil
+	evidence := map[string]string{
+		a.Name():         a.state.Mode,
+		"policy_version": a.state.PolicyVersion,
+		"policy_hash":    a.state.PolicyHash,
+		"mls":            strconv.FormatBool(a.state.MLS),
+		"deny_unknown":   strconv.FormatBool(a.state.DenyUnknown),
+		"checkreqprot":   strconv.FormatBool(a.state.CheckReqProt),
+		"booleans_count": strconv.Itoa(len(a.state.Booleans)),
+		"timestamp":      a.timestamp,
+	}
+
+	if a.state.Error != "" {
+		evidence["error"] = a.state.Error
+	}
+
+	for _, boolean := range a.state.Booleans {
+		evidence["boolean_"+boolean.Name] = strconv.FormatBool(boolean.Value)
+	}
+
+	return evidence, nil
+}
+
+// GetSELinuxState reads the SELinux mode and the loaded policy. Failures to read the state are
+// reported as ModeError rather than returned, so that an unreadable SELinux is attested explicitly.
+func GetSELinuxState() *State {
+	state := &State{
+		Booleans: make([]Boolean, 0),
+	}
+
+	mode, err := GetSELinuxMode()
+	if err != nil {
+		return withError(state, err)
+	}
+
+	state.Mode = mode
+	if mode == ModeAbsent {
+		return state
+	}
+
+	err = readPolicy(state)
+	if err != nil {
+		return withError(state, err)
+	}
+
+	state.Booleans, err = GetBooleans()
+	if err != nil {
+		return withError(state, err)
+	}
+
+	return state
 }
 
 // GetSELinuxMode checks the current SELinux mode on the machine.
 func GetSELinuxMode() (string, error) {
 	data, err := os.ReadFile(seLinuxEnabledPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return ModeAbsent, nil
+	} else if err != nil {
+		return ModeError, fmt.Errorf("failed to read SELinux enforce file: %w", err)
+	}
+
+	modes := map[string]string{"1": ModeEnforcing, "0": ModePermissive}
+
+	mode, ok := modes[strings.TrimSpace(string(data))]
+	if !ok {
+		return ModeError, fmt.Errorf("%w: %q", ErrUnknownEnforceValue, strings.TrimSpace(string(data)))
+	}
+
+	return mode, nil
+}
+
+// GetBooleans returns the SELinux policy booleans sorted by name with their current values.
+func GetBooleans() ([]Boolean, error) {
+	entries, err := os.ReadDir(seLinuxBooleansDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Boolean{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", seLinuxBooleansDir, err)
+	}
+
+	booleans := make([]Boolean, 0, len(entries))
+
+	for _, entry := range entries {
+		// Each boolean file holds the current and the pending value, e.g. "1 1".
+		value, err := utils.ReadValue(filepath.Join(seLinuxBooleansDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+
+		booleans = append(booleans, Boolean{
+			Name:  entry.Name(),
+			Value: fields[0] == "1",
+		})
+	}
+
+	sort.Slice(booleans, func(i, j int) bool {
+		return booleans[i].Name < booleans[j].Name
+	})
+
+	return booleans, nil
+}
+
+func readPolicy(state *State) error {
+	policy, err := os.ReadFile(seLinuxPolicyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read SELinux policy: %w", err)
+	}
+
+	state.PolicyHash = utils.EncodeMeasurement(policy)
+
+	state.PolicyVersion, err = utils.ReadValue(seLinuxPolicyVersPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read SELinux enforce file: %w", err)
+		return err
 	}
 
-	modes := map[string]string{"1": "Enforcing", "0": "Permissive"}
+	flags := map[string]*bool{
+		seLinuxMLSPath:          &state.MLS,
+		seLinuxDenyUnknownPath:  &state.DenyUnknown,
+		seLinuxCheckReqProtPath: &state.CheckReqProt,
+	}
+
+	for path, flag := range flags {
+		value, err := utils.ReadValue(path)
+		if err != nil {
+			return err
+		}
+
+		*flag = value == "1"
+	}
+
+	return nil
+}
+
+func withError(state *State, err error) *State {
+	state.Mode = ModeError
+	state.Error = err.Error()
 
-	return modes[strings.TrimSpace(string(data))], nil
+	return state
 }
@@ -0,0 +1,9 @@
+// Package smbios provides error definitions for SMBIOS operations.
+package smbios
+
+import "errors"
+
+var (
+	// ErrTruncatedTable is returned when a SMBIOS structure extends beyond the end of the table.
+	ErrTruncatedTable = errors.New("truncated SMBIOS table")
+)
@@ -0,0 +1,237 @@
+package smbios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	structureHeaderSize = 4
+	uuidSize            = 16
+	kilobytesPerMB      = 1024
+
+	typeBIOS         = 0
+	typeSystem       = 1
+	typeBaseboard    = 2
+	typeChassis      = 3
+	typeMemoryDevice = 17
+	typeEndOfTable   = 127
+
+	memorySizeNotInstalled = 0x0000
+	memorySizeUnknown      = 0xFFFF
+	memorySizeExtended     = 0x7FFF
+	memorySizeKilobytes    = 0x8000
+	chassisTypeMask        = 0x7F
+	biosReleaseUnsupported = 0xFF
+
+	entryPoint64Anchor = "_SM3_"
+	entryPoint32Anchor = "_SM_"
+)
+
+// structure represents a single SMBIOS structure with its formatted area and string set.
+type structure struct {
+	kind      byte
+	formatted []byte
+	strings   []string
+}
+
+// ParseIdentity parses the SMBIOS entry point and structure table into the hardware identity.
+func ParseIdentity(entryPoint []byte, table []byte) (*Identity, error) {
+	structures, err := parseStructures(table)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Version: parseVersion(entryPoint),
+		Memory:  make([]MemoryDevice, 0),
+	}
+
+	for _, s := range structures {
+		switch s.kind {
+		case typeBIOS:
+			identity.BIOS = BIOS{
+				Vendor:      s.stringAt(0x04),
+				Version:     s.stringAt(0x05),
+				ReleaseDate: s.stringAt(0x08),
+				Release:     s.biosRelease(),
+			}
+		case typeSystem:
+			identity.System = System{
+				Manufacturer: s.stringAt(0x04),
+				Product:      s.stringAt(0x05),
+				Version:      s.stringAt(0x06),
+				Serial:       s.stringAt(0x07),
+				UUID:         s.uuidAt(0x08),
+				SKU:          s.stringAt(0x19),
+				Family:       s.stringAt(0x1A),
+			}
+		case typeBaseboard:
+			identity.Baseboard = Baseboard{
+				Manufacturer: s.stringAt(0x04),
+				Product:      s.stringAt(0x05),
+				Version:      s.stringAt(0x06),
+				Serial:       s.stringAt(0x07),
+				AssetTag:     s.stringAt(0x08),
+			}
+		case typeChassis:
+			identity.Chassis = Chassis{
+				Manufacturer: s.stringAt(0x04),
+				Type:         int(s.byteAt(0x05) & chassisTypeMask),
+				Version:      s.stringAt(0x06),
+				Serial:       s.stringAt(0x07),
+				AssetTag:     s.stringAt(0x08),
+			}
+		case typeMemoryDevice:
+			size := s.memorySizeMB()
+			if size == 0 {
+				continue
+			}
+
+			identity.Memory = append(identity.Memory, MemoryDevice{
+				Locator:      s.stringAt(0x10),
+				BankLocator:  s.stringAt(0x11),
+				SizeMB:       size,
+				Speed:        int(s.wordAt(0x15)),
+				Manufacturer: s.stringAt(0x17),
+				Serial:       s.stringAt(0x18),
+				PartNumber:   s.stringAt(0x1A),
+			})
+		}
+	}
+
+	return identity, nil
+}
+
+func parseStructures(table []byte) ([]structure, error) {
+	structures := make([]structure, 0)
+
+	for offset := 0; offset+structureHeaderSize <= len(table); {
+		kind := table[offset]
+		length := int(table[offset+1])
+
+		if length < structureHeaderSize || offset+length > len(table) {
+			return nil, fmt.Errorf("%w: structure at offset %d", ErrTruncatedTable, offset)
+		}
+
+		// The string set follows the formatted area and is terminated by a double NUL.
+		rest := table[offset+length:]
+
+		end := bytes.Index(rest, []byte{0, 0})
+		if end < 0 {
+			return nil, fmt.Errorf("%w: strings of structure at offset %d", ErrTruncatedTable, offset)
+		}
+
+		strs := make([]string, 0)
+		if end > 0 {
+			for _, str := range bytes.Split(rest[:end], []byte{0}) {
+				strs = append(strs, string(bytes.TrimSpace(str)))
+			}
+		}
+
+		structures = append(structures, structure{
+			kind:      kind,
+			formatted: table[offset : offset+length],
+			strings:   strs,
+		})
+
+		if kind == typeEndOfTable {
+			break
+		}
+
+		offset += length + end + 2
+	}
+
+	return structures, nil
+}
+
+func parseVersion(entryPoint []byte) string {
+	switch {
+	case bytes.HasPrefix(entryPoint, []byte(entryPoint64Anchor)) && len(entryPoint) > 8:
+		return fmt.Sprintf("%d.%d", entryPoint[7], entryPoint[8])
+	case bytes.HasPrefix(entryPoint, []byte(entryPoint32Anchor)) && len(entryPoint) > 7:
+		return fmt.Sprintf("%d.%d", entryPoint[6], entryPoint[7])
+	default:
+		return ""
+	}
+}
+
+func (s *structure) byteAt(offset int) byte {
+	if offset >= len(s.formatted) {
+		return 0
+	}
+
+	return s.formatted[offset]
+}
+
+func (s *structure) wordAt(offset int) uint16 {
+	if offset+2 > len(s.formatted) {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint16(s.formatted[offset:])
+}
+
+func (s *structure) dwordAt(offset int) uint32 {
+	if offset+4 > len(s.formatted) {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(s.formatted[offset:])
+}
+
+// stringAt returns the string referenced by the 1-based index stored at the given offset.
+func (s *structure) stringAt(offset int) string {
+	index := int(s.byteAt(offset))
+	if index == 0 || index > len(s.strings) {
+		return ""
+	}
+
+	return s.strings[index-1]
+}
+
+// uuidAt returns the system UUID stored at the given offset, using the SMBIOS 2.6+ byte order.
+func (s *structure) uuidAt(offset int) string {
+	if offset+uuidSize > len(s.formatted) {
+		return ""
+	}
+
+	raw := s.formatted[offset : offset+uuidSize]
+	if bytes.Equal(raw, make([]byte, uuidSize)) || bytes.Equal(raw, bytes.Repeat([]byte{0xFF}, uuidSize)) {
+		return ""
+	}
+
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(raw[0:4]),
+		binary.LittleEndian.Uint16(raw[4:6]),
+		binary.LittleEndian.Uint16(raw[6:8]),
+		raw[8:10],
+		raw[10:16],
+	)
+}
+
+func (s *structure) biosRelease() string {
+	major, minor := s.byteAt(0x14), s.byteAt(0x15)
+	if len(s.formatted) <= 0x15 || major == biosReleaseUnsupported {
+		return ""
+	}
+
+	return fmt.Sprintf("%d.%d", major, minor)
+}
+
+// memorySizeMB returns the size of the memory device in megabytes, or 0 when no module is installed.
+func (s *structure) memorySizeMB() uint64 {
+	size := s.wordAt(0x0C)
+
+	switch {
+	case size == memorySizeNotInstalled, size == memorySizeUnknown:
+		return 0
+	case size == memorySizeExtended:
+		return uint64(s.dwordAt(0x1C))
+	case size&memorySizeKilobytes != 0:
+		return uint64(size&^memorySizeKilobytes) / kilobytesPerMB
+	default:
+		return uint64(size)
+	}
+}
@@ -0,0 +1,187 @@
+// Package smbios provides utilities to attest the hardware identity of the machine from the raw SMBIOS tables.
+package smbios
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	dmiTablesDir   = "/sys/firmware/dmi/tables"
+	entryPointPath = dmiTablesDir + "/smbios_entry_point"
+	dmiTablePath   = dmiTablesDir + "/DMI"
+)
+
+// Identity represents the stable hardware identity of the machine as reported by SMBIOS.
+type Identity struct {
+	Version   string         `yaml:"version"`
+	BIOS      BIOS           `yaml:"bios"`
+	System    System         `yaml:"system"`
+	Baseboard Baseboard      `yaml:"baseboard"`
+	Chassis   Chassis        `yaml:"chassis"`
+	Memory    []MemoryDevice `yaml:"memory"`
+}
+
+// BIOS represents the SMBIOS BIOS information (type 0).
+type BIOS struct {
+	Vendor      string `yaml:"vendor"`
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"releaseDate"`
+	Release     string `yaml:"release"`
+}
+
+// System represents the SMBIOS system information (type 1).
+type System struct {
+	Manufacturer string `yaml:"manufacturer"`
+	Product      string `yaml:"product"`
+	Version      string `yaml:"version"`
+	Serial       string `yaml:"serial"`
+	UUID         string `yaml:"uuid"`
+	SKU          string `yaml:"sku"`
+	Family       string `yaml:"family"`
+}
+
+// Baseboard represents the SMBIOS baseboard information (type 2).
+type Baseboard struct {
+	Manufacturer string `yaml:"manufacturer"`
+	Product      string `yaml:"product"`
+	Version      string `yaml:"version"`
+	Serial       string `yaml:"serial"`
+	AssetTag     string `yaml:"assetTag"`
+}
+
+// Chassis represents the SMBIOS chassis information (type 3).
+type Chassis struct {
+	Manufacturer string `yaml:"manufacturer"`
+	Type         int    `yaml:"type"`
+	Version      string `yaml:"version"`
+	Serial       string `yaml:"serial"`
+	AssetTag     string `yaml:"assetTag"`
+}
+
+// MemoryDevice represents a populated SMBIOS memory device (type 17).
+type MemoryDevice struct {
+	Locator      string `yaml:"locator"`
+	BankLocator  string `yaml:"bankLocator"`
+	SizeMB       uint64 `yaml:"sizeMB"`
+	Speed        int    `yaml:"speed"`
+	Manufacturer string `yaml:"manufacturer"`
+	Serial       string `yaml:"serial"`
+	PartNumber   string `yaml:"partNumber"`
+}
+
+// Attestable implements the report.Attestable interface for the SMBIOS hardware identity.
+type Attestable struct {
+	identity    *Identity
+	available   bool
+	fingerprint string
+	tableHash   string
+	timestamp   string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "smbios"
+}
+
+// Measure returns the hardware fingerprint digest of the SMBIOS identity.
+func (a *Attestable) Measure() (string, error) {
+	table, err := readTable()
+	if err != nil {
+		return "", fmt.Errorf("failed to read SMBIOS table: %w", err)
+	}
+
+	identity := &Identity{Memory: make([]MemoryDevice, 0)}
+
+	if table != nil {
+		identity, err = ParseIdentity(readEntryPoint(), table)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse SMBIOS table: %w", err)
+		}
+
+		a.tableHash = utils.EncodeMeasurement(table)
+	}
+
+	data, err := yaml.Marshal(identity)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SMBIOS identity: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.identity = identity
+	a.available = table != nil
+	a.fingerprint = utils.EncodeMeasurement(data)
+
+	return a.fingerprint, nil
+}
+
+// Evidence returns metadata about the SMBIOS hardware identity.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"available":              strconv.FormatBool(a.available),
+		"fingerprint":            a.fingerprint,
+		"table_hash":             a.tableHash,
+		"smbios_version":         a.identity.Version,
+		"bios_vendor":            a.identity.BIOS.Vendor,
+		"bios_version":           a.identity.BIOS.Version,
+		"bios_release_date":      a.identity.BIOS.ReleaseDate,
+		"bios_release":           a.identity.BIOS.Release,
+		"system_manufacturer":    a.identity.System.Manufacturer,
+		"system_product":         a.identity.System.Product,
+		"system_version":         a.identity.System.Version,
+		"system_serial":          a.identity.System.Serial,
+		"system_uuid":            a.identity.System.UUID,
+		"system_sku":             a.identity.System.SKU,
+		"system_family":          a.identity.System.Family,
+		"baseboard_manufacturer": a.identity.Baseboard.Manufacturer,
+		"baseboard_product":      a.identity.Baseboard.Product,
+		"baseboard_version":      a.identity.Baseboard.Version,
+		"baseboard_serial":       a.identity.Baseboard.Serial,
+		"baseboard_asset_tag":    a.identity.Baseboard.AssetTag,
+		"chassis_manufacturer":   a.identity.Chassis.Manufacturer,
+		"chassis_type":           strconv.Itoa(a.identity.Chassis.Type),
+		"chassis_version":        a.identity.Chassis.Version,
+		"chassis_serial":         a.identity.Chassis.Serial,
+		"chassis_asset_tag":      a.identity.Chassis.AssetTag,
+		"memory_devices_count":   strconv.Itoa(len(a.identity.Memory)),
+		"timestamp":              a.timestamp,
+	}
+
+	for i, device := range a.identity.Memory {
+		prefix := fmt.Sprintf("memory_device_%d_", i)
+		evidence[prefix+"locator"] = device.Locator
+		evidence[prefix+"bank_locator"] = device.BankLocator
+		evidence[prefix+"size_mb"] = strconv.FormatUint(device.SizeMB, 10)
+		evidence[prefix+"speed"] = strconv.Itoa(device.Speed)
+		evidence[prefix+"manufacturer"] = device.Manufacturer
+		evidence[prefix+"serial"] = device.Serial
+		evidence[prefix+"part_number"] = device.PartNumber
+	}
+
+	return evidence, nil
+}
+
+func readTable() ([]byte, error) {
+	data, err := os.ReadFile(dmiTablePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dmiTablePath, err)
+	}
+
+	return data, nil
+}
+
+func readEntryPoint() []byte {
+	data, err := os.ReadFile(entryPointPath)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,9 @@
+// Package sockets provides error definitions for socket table operations.
+package sockets
+
+import "errors"
+
+var (
+	// ErrInvalidAddress is returned when a socket address in a /proc/net socket table cannot be parsed.
+	ErrInvalidAddress = errors.New("invalid socket address")
+)
@@ -0,0 +1,191 @@
+package sockets
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+)
+
+const (
+	procPath            = "/proc"
+	procNetPath         = "/proc/net"
+	socketLinkPrefix    = "socket:["
+	socketLinkSuffix    = "]"
+	socketTableFields   = 10
+	remoteAddressField  = 2
+	stateField          = 3
+	uidField            = 7
+	inodeField          = 9
+	tcpStateListen      = "0A"
+	udpStateUnconnected = "07"
+	ipv4AddressLength   = 4
+	ipv6AddressLength   = 16
+	ipv6WordLength      = 4
+)
+
+// socketTable describes a socket table in /proc/net and the state of its listening sockets. UDP has no
+// listening state: every unconnected UDP socket accepts datagrams from any peer on its port, so it is a listener
+// whatever the port is. Client sockets, e.g. of DNS lookups, are connected to their server and have a remote
+// address, so requireUnconnected skips them.
+type socketTable struct {
+	protocol           string
+	state              string
+	requireUnconnected bool
+}
+
+//nolint:gochecknoglobals
+var socketTables = []socketTable{
+	{protocol: "tcp", state: tcpStateListen},
+	{protocol: "tcp6", state: tcpStateListen},
+	{protocol: "udp", state: udpStateUnconnected, requireUnconnected: true},
+	{protocol: "udp6", state: udpStateUnconnected, requireUnconnected: true},
+}
+
+type owner struct {
+	pid     int
+	process string
+}
+
+// readSocketTable parses the listening sockets of a /proc/net socket table with lines of the format
+// "<sl> <local address> <remote address> <state> <tx:rx queue> <timer> <retransmits> <uid> <timeout> <inode> ...".
+func readSocketTable(table socketTable) ([]Socket, error) {
+	path := filepath.Join(procNetPath, table.protocol)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Socket{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	sockets := make([]Socket, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < socketTableFields || fields[stateField] != table.state {
+			continue
+		}
+
+		address, port, err := parseAddress(fields[1])
+		if err != nil || (table.requireUnconnected && !isUnconnected(fields[remoteAddressField])) {
+			continue
+		}
+
+		sockets = append(sockets, Socket{
+			Protocol: table.protocol,
+			Address:  address,
+			Port:     port,
+			UID:      fields[uidField],
+			Inode:    fields[inodeField],
+		})
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+
+	return sockets, nil
+}
+
+// isUnconnected reports whether the remote address of a socket is the wildcard address with port zero.
+func isUnconnected(value string) bool {
+	address, port, err := parseAddress(value)
+	if err != nil {
+		return false
+	}
+
+	return port == 0 && net.ParseIP(address).IsUnspecified()
+}
+
+// parseAddress parses a "<address>:<port>" pair in hex, where the address is stored as native endian 32-bit words.
+func parseAddress(value string) (string, uint16, error) {
+	addressHex, portHex, found := strings.Cut(value, ":")
+	if !found {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+
+	raw, err := hex.DecodeString(addressHex)
+	if err != nil || (len(raw) != ipv4AddressLength && len(raw) != ipv6AddressLength) {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+
+	ip := make(net.IP, len(raw))
+	for offset := 0; offset < len(raw); offset += ipv6WordLength {
+		binary.BigEndian.PutUint32(ip[offset:], binary.NativeEndian.Uint32(raw[offset:]))
+	}
+
+	return ip.String(), uint16(port), nil
+}
+
+// getSocketOwners maps socket inodes to the owning process by scanning /proc/*/fd. Processes whose
+// file descriptors cannot be read are skipped.
+func getSocketOwners() map[string]owner {
+	owners := make(map[string]owner)
+
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return owners
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join(procPath, entry.Name(), "fd")
+
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		var process string
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, socketLinkPrefix) {
+				continue
+			}
+
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, socketLinkPrefix), socketLinkSuffix)
+
+			_, ok := owners[inode]
+			if ok {
+				continue
+			}
+
+			if process == "" {
+				process = readComm(pid)
+			}
+
+			owners[inode] = owner{pid: pid, process: process}
+		}
+	}
+
+	return owners
+}
+
+func readComm(pid int) string {
+	return utils.ReadSysfsValue(filepath.Join(procPath, strconv.Itoa(pid), "comm"))
+}
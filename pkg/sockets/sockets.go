@@ -0,0 +1,156 @@
+// Package sockets provides utilities to attest the listening network sockets of the machine.
+package sockets
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+// Socket represents a listening socket and the process owning it. The owning process changes with restarts
+// and is only reported as evidence.
+type Socket struct {
+	Protocol string `yaml:"protocol"`
+	Address  string `yaml:"address"`
+	Port     uint16 `yaml:"port"`
+	Process  string `yaml:"-"`
+	UID      string `yaml:"-"`
+	PID      int    `yaml:"-"`
+	Inode    string `yaml:"-"`
+}
+
+// Loopback reports whether the socket is only reachable from the machine itself.
+func (s Socket) Loopback() bool {
+	ip := net.ParseIP(s.Address)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// Attestable implements the report.Attestable interface for the listening network sockets.
+type Attestable struct {
+	sockets   []Socket
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "listening-sockets"
+}
+
+// Measure returns the measurement of the set of exposed services.
+func (a *Attestable) Measure() (string, error) {
+	sockets, err := GetListeningSockets()
+	if err != nil {
+		return "", fmt.Errorf("failed to get listening sockets: %w", err)
+	}
+
+	data, err := yaml.Marshal(services(sockets))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal listening sockets: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.sockets = sockets
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about each listening socket.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"sockets_count": strconv.Itoa(len(a.sockets)),
+		"timestamp":     a.timestamp,
+	}
+
+	exposed := make([]string, 0)
+
+	for i, socket := range a.sockets {
+		prefix := fmt.Sprintf("socket_%d_", i)
+		port := strconv.FormatUint(uint64(socket.Port), 10)
+
+		evidence[prefix+"protocol"] = socket.Protocol
+		evidence[prefix+"address"] = socket.Address
+		evidence[prefix+"port"] = port
+		evidence[prefix+"process"] = socket.Process
+		evidence[prefix+"uid"] = socket.UID
+
+		if socket.PID != 0 {
+			evidence[prefix+"pid"] = strconv.Itoa(socket.PID)
+		}
+
+		service := socket.Protocol + "/" + port
+		if !socket.Loopback() && !slices.Contains(exposed, service) {
+			exposed = append(exposed, service)
+		}
+	}
+
+	evidence["exposed_count"] = strconv.Itoa(len(exposed))
+	evidence["exposed_ports"] = strings.Join(exposed, ",")
+
+	return evidence, nil
+}
+
+// GetListeningSockets returns the listening TCP sockets and the unconnected UDP sockets, sorted by
+// protocol, port and address, with the owning process when it can be resolved.
+func GetListeningSockets() ([]Socket, error) {
+	sockets := make([]Socket, 0)
+
+	for _, table := range socketTables {
+		entries, err := readSocketTable(table)
+		if err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, entries...)
+	}
+
+	owners := getSocketOwners()
+
+	for i, socket := range sockets {
+		owner, ok := owners[socket.Inode]
+		if ok {
+			sockets[i].PID = owner.pid
+			sockets[i].Process = owner.process
+		}
+	}
+
+	sort.Slice(sockets, func(i, j int) bool {
+		if sockets[i].Protocol != sockets[j].Protocol {
+			return sockets[i].Protocol < sockets[j].Protocol
+		}
+
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+
+		return sockets[i].Address < sockets[j].Address
+	})
+
+	return sockets, nil
+}
+
+// services returns the deduplicated set of exposed services, since a service may listen on multiple
+// sockets, e.g. with SO_REUSEPORT.
+func services(sockets []Socket) []Socket {
+	set := make([]Socket, 0, len(sockets))
+
+	for _, socket := range sockets {
+		service := Socket{
+			Protocol: socket.Protocol,
+			Address:  socket.Address,
+			Port:     socket.Port,
+		}
+
+		if !slices.Contains(set, service) {
+			set = append(set, service)
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,141 @@
+package taint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	kmsgPath         = "/dev/kmsg"
+	kmsgRecordSize   = 8192
+	kmsgHeaderSep    = ";"
+	recentEventCount = 3
+)
+
+// Events represents the kernel log records of a single security event category.
+type Events struct {
+	Category string   `yaml:"category"`
+	Count    int      `yaml:"count"`
+	Recent   []string `yaml:"-"`
+}
+
+// eventCategory matches kernel log records of a security event category by substring. The patterns only
+// match failures and violations, not the state the kernel logs on every boot.
+type eventCategory struct {
+	name     string
+	patterns []string
+}
+
+//nolint:gochecknoglobals
+var eventCategories = []eventCategory{
+	{
+		name:     "lockdown",
+		patterns: []string{"Lockdown: "},
+	},
+	{
+		name: "module_signature",
+		patterns: []string{
+			"module verification failed",
+			"Loading of unsigned module",
+			"Loading of module with unavailable key",
+			"PKCS#7 signature not signed with a trusted key",
+		},
+	},
+	{
+		name:     "ima_appraisal",
+		patterns: []string{"op=appraise", "cause=invalid-signature", "cause=missing-hash", "cause=IMA-signature-required"},
+	},
+	{
+		name: "secureboot",
+		patterns: []string{
+			"Secure boot could not be determined",
+			"signature verification failed",
+			"PEFILE: Digest mismatch",
+			"Problem loading X.509 certificate",
+		},
+	},
+}
+
+// GetKmsgEvents scans the kernel log from boot for security events. The second return value reports
+// whether the kernel log could be read at all.
+func GetKmsgEvents() ([]Events, bool, error) {
+	events := make([]Events, 0, len(eventCategories))
+	for _, category := range eventCategories {
+		events = append(events, Events{
+			Category: category.name,
+			Recent:   make([]string, 0),
+		})
+	}
+
+	fd, err := unix.Open(kmsgPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.EACCES) || errors.Is(err, unix.EPERM) {
+		return events, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to open %s: %w", kmsgPath, err)
+	}
+
+	defer func() {
+		_ = unix.Close(fd)
+	}()
+
+	buffer := make([]byte, kmsgRecordSize)
+
+	for {
+		// Each read returns a single record; EPIPE signals records overwritten while reading.
+		n, err := unix.Read(fd, buffer)
+		if errors.Is(err, unix.EAGAIN) {
+			break
+		} else if errors.Is(err, unix.EPIPE) || errors.Is(err, unix.EINTR) {
+			continue
+		} else if err != nil {
+			return nil, false, fmt.Errorf("failed to read %s: %w", kmsgPath, err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		message := parseRecord(string(buffer[:n]))
+
+		for i, category := range eventCategories {
+			if !matches(message, category.patterns) {
+				continue
+			}
+
+			events[i].Count++
+			events[i].Recent = append(events[i].Recent, message)
+
+			if len(events[i].Recent) > recentEventCount {
+				events[i].Recent = events[i].Recent[1:]
+			}
+		}
+	}
+
+	return events, true, nil
+}
+
+// parseRecord returns the message of a /dev/kmsg record of the format "<prio>,<seq>,<usec>,<flags>;<message>".
+func parseRecord(record string) string {
+	_, message, found := strings.Cut(record, kmsgHeaderSep)
+	if !found {
+		message = record
+	}
+
+	// Continuation lines with the structured dictionary follow the first line of the message.
+	message, _, _ = strings.Cut(message, "\n")
+
+	return strings.TrimSpace(message)
+}
+
+func matches(message string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package taint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	pstorePath = "/sys/fs/pstore"
+)
+
+// GetPstoreRecords returns the names of the crash records left in pstore by previous boots.
+func GetPstoreRecords() ([]string, error) {
+	entries, err := os.ReadDir(pstorePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", pstorePath, err)
+	}
+
+	records := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			records = append(records, entry.Name())
+		}
+	}
+
+	sort.Strings(records)
+
+	return records, nil
+}
@@ -0,0 +1,187 @@
+// Package taint provides utilities to attest the kernel taint state, crash records and security-relevant kernel log.
+package taint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	taintedPath = "/proc/sys/kernel/tainted"
+)
+
+// taintFlag represents a single kernel taint flag as documented in Documentation/admin-guide/tainted-kernels.rst.
+type taintFlag struct {
+	Bit    uint
+	Letter string
+	Name   string
+}
+
+//nolint:gochecknoglobals
+var flags = []taintFlag{
+	{Bit: 0, Letter: "P", Name: "proprietary_module"},
+	{Bit: 1, Letter: "F", Name: "forced_module"},
+	{Bit: 2, Letter: "S", Name: "cpu_out_of_spec"},
+	{Bit: 3, Letter: "R", Name: "forced_rmmod"},
+	{Bit: 4, Letter: "M", Name: "machine_check"},
+	{Bit: 5, Letter: "B", Name: "bad_page"},
+	{Bit: 6, Letter: "U", Name: "user"},
+	{Bit: 7, Letter: "D", Name: "oops"},
+	{Bit: 8, Letter: "A", Name: "overridden_acpi_table"},
+	{Bit: 9, Letter: "W", Name: "warning"},
+	{Bit: 10, Letter: "C", Name: "staging_driver"},
+	{Bit: 11, Letter: "I", Name: "firmware_workaround"},
+	{Bit: 12, Letter: "O", Name: "out_of_tree_module"},
+	{Bit: 13, Letter: "E", Name: "unsigned_module"},
+	{Bit: 14, Letter: "L", Name: "soft_lockup"},
+	{Bit: 15, Letter: "K", Name: "livepatch"},
+	{Bit: 16, Letter: "X", Name: "auxiliary"},
+	{Bit: 17, Letter: "T", Name: "randstruct"},
+	{Bit: 18, Letter: "N", Name: "test"},
+}
+
+// State represents the kernel taint flags, the pstore crash records and the security events of the kernel log.
+// The kernel log is a ring buffer, so the security events are only reported as evidence.
+type State struct {
+	Tainted       uint64   `yaml:"tainted"`
+	Flags         []string `yaml:"flags"`
+	PstoreRecords []string `yaml:"pstoreRecords"`
+	KmsgAvailable bool     `yaml:"kmsgAvailable"`
+	Events        []Events `yaml:"-"`
+}
+
+// Attestable implements the report.Attestable interface for the kernel taint state and security events.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "kernel-taint"
+}
+
+// Measure returns the measurement of the kernel taint flags and the crash records.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetTaintState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kernel taint state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal kernel taint state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the kernel taint flags, the crash records and the security events.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"tainted":        strconv.FormatUint(a.state.Tainted, 10),
+		"taint_flags":    strings.Join(a.state.Flags, ","),
+		"taint_letters":  decodeLetters(a.state.Tainted),
+		"pstore_records": strconv.Itoa(len(a.state.PstoreRecords)),
+		"pstore_files":   strings.Join(a.state.PstoreRecords, ","),
+		"kmsg_available": strconv.FormatBool(a.state.KmsgAvailable),
+		"timestamp":      a.timestamp,
+	}
+
+	for _, events := range a.state.Events {
+		evidence[events.Category+"_count"] = strconv.Itoa(events.Count)
+
+		for i, line := range events.Recent {
+			evidence[fmt.Sprintf("%s_recent_%d", events.Category, i)] = line
+		}
+	}
+
+	return evidence, nil
+}
+
+// GetTaintState reads the kernel taint flags, the pstore crash records and the security events of the kernel log.
+func GetTaintState() (*State, error) {
+	tainted, err := GetTainted()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := GetPstoreRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	events, available, err := GetKmsgEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Tainted:       tainted,
+		Flags:         DecodeFlags(tainted),
+		PstoreRecords: records,
+		KmsgAvailable: available,
+		Events:        events,
+	}, nil
+}
+
+// GetTainted reads the kernel taint bitmask.
+func GetTainted() (uint64, error) {
+	data, err := os.ReadFile(taintedPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", taintedPath, err)
+	}
+
+	tainted, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", taintedPath, err)
+	}
+
+	return tainted, nil
+}
+
+// DecodeFlags returns the names of the taint flags set in the bitmask. Unknown bits are reported by number.
+func DecodeFlags(tainted uint64) []string {
+	names := make([]string, 0)
+	known := uint64(0)
+
+	for _, flag := range flags {
+		known |= 1 << flag.Bit
+
+		if tainted&(1<<flag.Bit) != 0 {
+			names = append(names, flag.Name)
+		}
+	}
+
+	for bit := range uint(64) {
+		if tainted&(1<<bit) != 0 && known&(1<<bit) == 0 {
+			names = append(names, "bit_"+strconv.FormatUint(uint64(bit), 10))
+		}
+	}
+
+	return names
+}
+
+// decodeLetters returns the taint letters as printed by the kernel in oops reports, e.g. "PO".
+func decodeLetters(tainted uint64) string {
+	var letters strings.Builder
+
+	for _, flag := range flags {
+		if tainted&(1<<flag.Bit) != 0 {
+			letters.WriteString(flag.Letter)
+		}
+	}
+
+	return letters.String()
+}
@@ -0,0 +1,107 @@
+package timesync
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// talosConfigPath is the machine configuration on the STATE partition of the host, reached through the root
+	// of init as the extension container does not mount /system/state.
+	talosConfigPath    = "/proc/1/root/system/state/config.yaml"
+	timeSyncConfigKind = "TimeSyncConfig"
+
+	// SourceConfig is reported when the NTP servers are read from the Talos machine configuration.
+	SourceConfig = "config"
+	// SourceDefault is reported when the machine configuration sets no NTP servers and Talos uses its default.
+	SourceDefault = "default"
+	// SourceUnavailable is reported when the Talos machine configuration cannot be read or parsed by the extension.
+	SourceUnavailable = "unavailable"
+)
+
+//nolint:gochecknoglobals
+var defaultNTPServers = []string{"time.cloudflare.com"}
+
+// NTPServers represents the NTP servers configured for the machine and where they were read from.
+type NTPServers struct {
+	Servers  []string
+	Source   string
+	Disabled bool
+}
+
+// configDocument holds the fields of the Talos configuration documents which configure time synchronization,
+// the machine.time section of the v1alpha1 document and the TimeSyncConfig document.
+type configDocument struct {
+	Kind    string `yaml:"kind"`
+	Enabled *bool  `yaml:"enabled"`
+	NTP     struct {
+		Servers []string `yaml:"servers"`
+	} `yaml:"ntp"`
+	Machine struct {
+		Time struct {
+			Disabled bool     `yaml:"disabled"`
+			Servers  []string `yaml:"servers"`
+		} `yaml:"time"`
+	} `yaml:"machine"`
+}
+
+// GetNTPServers reads the NTP servers from the Talos machine configuration of the host. A configuration which
+// cannot be parsed is treated as unavailable, so that it does not abort the report.
+func GetNTPServers() (*NTPServers, error) {
+	unavailable := &NTPServers{Servers: []string{}, Source: SourceUnavailable}
+
+	file, err := os.Open(talosConfigPath)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return unavailable, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", talosConfigPath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	servers := &NTPServers{
+		Servers: []string{},
+		Source:  SourceDefault,
+	}
+
+	decoder := yaml.NewDecoder(file)
+
+	for {
+		var document configDocument
+
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return unavailable, nil //nolint:nilerr // An unparsable configuration is reported as unavailable
+		}
+
+		switch {
+		case document.Kind == timeSyncConfigKind:
+			servers.Servers = append(servers.Servers, document.NTP.Servers...)
+			servers.Disabled = servers.Disabled || (document.Enabled != nil && !*document.Enabled)
+		case len(document.Machine.Time.Servers) > 0 || document.Machine.Time.Disabled:
+			servers.Servers = append(servers.Servers, document.Machine.Time.Servers...)
+			servers.Disabled = servers.Disabled || document.Machine.Time.Disabled
+		}
+	}
+
+	for i, server := range servers.Servers {
+		servers.Servers[i] = strings.TrimSpace(server)
+	}
+
+	if len(servers.Servers) > 0 {
+		servers.Source = SourceConfig
+	} else {
+		servers.Servers = append(servers.Servers, defaultNTPServers...)
+	}
+
+	return servers, nil
+}
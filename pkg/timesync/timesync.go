@@ -0,0 +1,210 @@
+// Package timesync provides utilities to attest whether the clock of the machine can be trusted.
+//
+// The configured NTP servers are read from the Talos machine configuration on the STATE partition. That file
+// also holds secrets, such as the cluster CA keys and the bootstrap tokens, so the extension must be trusted
+// with it; only the time synchronization settings are decoded and nothing else of it is reported.
+package timesync
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/tpm"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	clockSourcePath          = "/sys/devices/system/clocksource/clocksource0/current_clocksource"
+	availableClockSourcePath = "/sys/devices/system/clocksource/clocksource0/available_clocksource"
+)
+
+//nolint:gochecknoglobals
+var clockStates = map[int]string{
+	unix.TIME_OK:    "ok",
+	unix.TIME_INS:   "insert-leap-second",
+	unix.TIME_DEL:   "delete-leap-second",
+	unix.TIME_OOP:   "leap-second-in-progress",
+	unix.TIME_WAIT:  "leap-second-occurred",
+	unix.TIME_ERROR: "error",
+}
+
+// Kernel represents the clock discipline state of the kernel as reported by adjtimex.
+type Kernel struct {
+	Synchronized bool   `yaml:"synchronized"`
+	State        string `yaml:"-"`
+	Status       int32  `yaml:"-"`
+	EstError     int64  `yaml:"-"`
+	MaxError     int64  `yaml:"-"`
+	Offset       int64  `yaml:"-"`
+}
+
+// TPMClock represents the clock of the TPM compared to the clock of the machine.
+type TPMClock struct {
+	Available bool   `yaml:"available"`
+	Time      uint64 `yaml:"-"`
+	Clock     uint64 `yaml:"-"`
+	// UptimeDelta is the difference in milliseconds between the boot time of the kernel and the TPM time,
+	// which both count from the last reset of the machine.
+	UptimeDelta int64 `yaml:"-"`
+	// BootWallClock is the wall clock time in milliseconds at which the TPM time started counting. It stays
+	// constant across reports of the same boot unless the wall clock is stepped.
+	BootWallClock int64 `yaml:"-"`
+}
+
+// State represents the time synchronization trust of the machine.
+type State struct {
+	Kernel                Kernel   `yaml:"kernel"`
+	ClockSource           string   `yaml:"clockSource"`
+	AvailableClockSources []string `yaml:"-"`
+	NTPServers            []string `yaml:"ntpServers"`
+	NTPServersSource      string   `yaml:"-"`
+	TimeSyncDisabled      bool     `yaml:"timeSyncDisabled"`
+	TPMClock              TPMClock `yaml:"tpmClock"`
+	WallClock             int64    `yaml:"-"`
+}
+
+// Attestable implements the report.Attestable interface for the time synchronization trust.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "time"
+}
+
+// Measure returns the measurement of the synchronization status, the clock source and the NTP servers.
+// The clock errors drift continuously and are only reported as evidence, as is the source of the NTP servers.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetTimeState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get time synchronization state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal time synchronization state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the clock discipline, the NTP servers and the TPM clock.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"synchronized":        strconv.FormatBool(a.state.Kernel.Synchronized),
+		"sta_unsync":          strconv.FormatBool(a.state.Kernel.Status&unix.STA_UNSYNC != 0),
+		"clock_state":         a.state.Kernel.State,
+		"status":              strconv.FormatInt(int64(a.state.Kernel.Status), 10),
+		"esterror_us":         strconv.FormatInt(a.state.Kernel.EstError, 10),
+		"maxerror_us":         strconv.FormatInt(a.state.Kernel.MaxError, 10),
+		"offset":              strconv.FormatInt(a.state.Kernel.Offset, 10),
+		"clock_source":        a.state.ClockSource,
+		"available_sources":   strings.Join(a.state.AvailableClockSources, ","),
+		"ntp_servers":         strings.Join(a.state.NTPServers, ","),
+		"ntp_servers_source":  a.state.NTPServersSource,
+		"time_sync_disabled":  strconv.FormatBool(a.state.TimeSyncDisabled),
+		"wall_clock_ms":       strconv.FormatInt(a.state.WallClock, 10),
+		"tpm_clock_available": strconv.FormatBool(a.state.TPMClock.Available),
+		"timestamp":           a.timestamp,
+	}
+
+	if a.state.TPMClock.Available {
+		evidence["tpm_time_ms"] = strconv.FormatUint(a.state.TPMClock.Time, 10)
+		evidence["tpm_clock_ms"] = strconv.FormatUint(a.state.TPMClock.Clock, 10)
+		evidence["tpm_uptime_delta_ms"] = strconv.FormatInt(a.state.TPMClock.UptimeDelta, 10)
+		evidence["tpm_boot_wall_clock_ms"] = strconv.FormatInt(a.state.TPMClock.BootWallClock, 10)
+	}
+
+	return evidence, nil
+}
+
+// GetTimeState reads the clock discipline of the kernel, the clock source, the configured NTP servers
+// and compares the clock of the machine with the clock of the TPM.
+func GetTimeState() (*State, error) {
+	kernel, err := GetKernelClock()
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := GetNTPServers()
+	if err != nil {
+		return nil, err
+	}
+
+	tpmClock, err := GetTPMClock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Kernel:                *kernel,
+		ClockSource:           utils.ReadSysfsValue(clockSourcePath),
+		AvailableClockSources: strings.Fields(utils.ReadSysfsValue(availableClockSourcePath)),
+		NTPServers:            servers.Servers,
+		NTPServersSource:      servers.Source,
+		TimeSyncDisabled:      servers.Disabled,
+		TPMClock:              *tpmClock,
+		WallClock:             time.Now().UnixMilli(),
+	}, nil
+}
+
+// GetKernelClock queries adjtimex for the clock discipline state without modifying it.
+func GetKernelClock() (*Kernel, error) {
+	var timex unix.Timex
+
+	state, err := unix.Adjtimex(&timex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query adjtimex: %w", err)
+	}
+
+	name, ok := clockStates[state]
+	if !ok {
+		name = strconv.Itoa(state)
+	}
+
+	return &Kernel{
+		Synchronized: state != unix.TIME_ERROR && timex.Status&unix.STA_UNSYNC == 0,
+		State:        name,
+		Status:       timex.Status,
+		EstError:     timex.Esterror,
+		MaxError:     timex.Maxerror,
+		Offset:       timex.Offset,
+	}, nil
+}
+
+// GetTPMClock reads the TPM clock and compares the TPM time with the boot time of the kernel.
+// When the machine has no TPM, the TPM clock is reported as unavailable.
+func GetTPMClock() (*TPMClock, error) {
+	tpmTime, tpmClock, err := tpm.ReadClock()
+	if errors.Is(err, tpm.ErrTPMNotFound) {
+		return &TPMClock{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	uptime, err := utils.Uptime()
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // TPM time in milliseconds fits in int64
+	elapsed := int64(tpmTime)
+
+	return &TPMClock{
+		Available:     true,
+		Time:          tpmTime,
+		Clock:         tpmClock,
+		UptimeDelta:   uptime.Milliseconds() - elapsed,
+		BootWallClock: time.Now().UnixMilli() - elapsed,
+	}, nil
+}
@@ -178,3 +178,25 @@ func GetPCRSelection(pcrIndices []int) *tpm2.PCRSelection {
 		PCRs: pcrIndices,
 	}
 }
+
+// ReadClock reads the clock of the TPM. It returns the time in milliseconds since the last TPM reset and
+// the clock in milliseconds the TPM has been powered, which persists across resets.
+func ReadClock() (uint64, uint64, error) {
+	device := Device{}
+
+	err := device.openTPM()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer func() {
+		_ = device.Close()
+	}()
+
+	time, clock, err := tpm2.ReadClock(device.rwc)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read TPM clock: %w", err)
+	}
+
+	return time, clock, nil
+}
@@ -0,0 +1,11 @@
+// Package uki provides error definitions for UKI operations.
+package uki
+
+import "errors"
+
+var (
+	// ErrNotPEImage is returned when the given file is not a valid PE image.
+	ErrNotPEImage = errors.New("file is not a valid PE image")
+	// ErrNoLinuxSection is returned when the PE image has no .linux section and is therefore not a UKI.
+	ErrNoLinuxSection = errors.New("PE image has no .linux section")
+)
@@ -0,0 +1,150 @@
+package uki
+
+import (
+	"crypto/sha256"
+	"debug/pe"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	sectionPCRPublicKey = ".pcrpkey"
+)
+
+// measuredSections lists the UKI sections in the order systemd-stub measures them into PCR 11.
+//
+//nolint:gochecknoglobals
+var measuredSections = []string{
+	".linux",
+	".osrel",
+	".cmdline",
+	".initrd",
+	".ucode",
+	".splash",
+	".dtb",
+	".uname",
+	".sbat",
+	sectionPCRPublicKey,
+}
+
+// talosPhases lists the boot phases Talos extends into PCR 11 after systemd-stub has run, in order.
+//
+//nolint:gochecknoglobals
+var talosPhases = []string{
+	"enter-initrd",
+	"leave-initrd",
+	"enter-machined",
+	"start-the-world",
+}
+
+// Prediction represents the expected PCR 11 values for a UKI.
+type Prediction struct {
+	Path         string    `yaml:"-"`
+	Sections     []Section `yaml:"sections"`
+	PCR          string    `yaml:"pcr"`
+	Phases       []Phase   `yaml:"phases"`
+	PCRPublicKey string    `yaml:"pcrPublicKey"`
+}
+
+// Section represents a single measured UKI section and the SHA-256 digest of its contents.
+type Section struct {
+	Name   string `yaml:"name"`
+	Digest string `yaml:"digest"`
+}
+
+// Phase represents the expected PCR 11 value after Talos has extended the given boot phase.
+type Phase struct {
+	Name string `yaml:"name"`
+	PCR  string `yaml:"pcr"`
+}
+
+// PredictPCR11 predicts the SHA-256 PCR 11 values for the UKI at the given path, as measured by
+// systemd-stub and extended by the Talos boot phases. The path can point to a UKI on the node or
+// to one given offline.
+func PredictPCR11(path string) (*Prediction, error) {
+	file, err := pe.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrNotPEImage, path, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if file.Section(".linux") == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoLinuxSection, path)
+	}
+
+	prediction := &Prediction{
+		Path:     path,
+		Sections: make([]Section, 0, len(measuredSections)),
+		Phases:   make([]Phase, 0, len(talosPhases)),
+	}
+
+	pcr := make([]byte, sha256.Size)
+
+	for _, name := range measuredSections {
+		section := file.Section(name)
+		if section == nil {
+			continue
+		}
+
+		data, err := sectionData(section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read section %s of %s: %w", name, path, err)
+		}
+
+		// systemd-stub measures the section name including its trailing NUL, then the contents.
+		pcr = extendPCR(pcr, append([]byte(name), 0))
+		pcr = extendPCR(pcr, data)
+
+		digest := sha256.Sum256(data)
+
+		prediction.Sections = append(prediction.Sections, Section{
+			Name:   name,
+			Digest: hex.EncodeToString(digest[:]),
+		})
+
+		if name == sectionPCRPublicKey {
+			prediction.PCRPublicKey = string(data)
+		}
+	}
+
+	prediction.PCR = hex.EncodeToString(pcr)
+
+	for _, phase := range talosPhases {
+		pcr = extendPCR(pcr, []byte(phase))
+
+		prediction.Phases = append(prediction.Phases, Phase{
+			Name: phase,
+			PCR:  hex.EncodeToString(pcr),
+		})
+	}
+
+	return prediction, nil
+}
+
+// sectionData returns the section contents without the file alignment padding, as systemd-stub
+// only measures the virtual size of each section.
+func sectionData(section *pe.Section) ([]byte, error) {
+	data, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read section data: %w", err)
+	}
+
+	if section.VirtualSize > 0 && int(section.VirtualSize) < len(data) {
+		return data[:section.VirtualSize], nil
+	}
+
+	return data, nil
+}
+
+func extendPCR(pcr []byte, data []byte) []byte {
+	digest := sha256.Sum256(data)
+
+	hash := sha256.New()
+	_, _ = hash.Write(pcr)
+	_, _ = hash.Write(digest[:])
+
+	return hash.Sum(nil)
+}
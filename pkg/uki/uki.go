@@ -0,0 +1,269 @@
+// Package uki provides utilities to attest the Unified Kernel Image measured into PCR 11 by systemd-stub.
+package uki
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	efiVarsDir          = "/sys/firmware/efi/efivars"
+	loaderVendorGUID    = "4a67b082-0a4c-41cf-b6c7-440b29bb8c4f" // systemd boot loader interface vendor
+	stubInfoVar         = "StubInfo"
+	loaderImageIDVar    = "LoaderImageIdentifier"
+	stubPCRKernelVar    = "StubPcrKernelImage"
+	efiVarAttributesLen = 4
+	pcr11Path           = "/sys/class/tpm/tpm0/pcr-sha256/11"
+	pcr11MatchNone      = "none"
+	pcr11MatchSections  = "sections"
+	// hostRoot is the root filesystem of the host, as the extension container only mounts /dev, /proc and /sys.
+	hostRoot = "/proc/1/root"
+)
+
+// espMountPoints lists the locations where the EFI system partition may be mounted.
+//
+//nolint:gochecknoglobals
+var espMountPoints = []string{"/boot", "/boot/efi", "/efi"}
+
+// searchRoots lists the roots under which the UKI is looked up: the host and the extension container.
+//
+//nolint:gochecknoglobals
+var searchRoots = []string{hostRoot, "/"}
+
+// StubInfo represents the information systemd-stub exposes through EFI variables.
+type StubInfo struct {
+	Info            string `yaml:"info"`
+	ImageIdentifier string `yaml:"imageIdentifier"`
+	PCRKernelImage  string `yaml:"pcrKernelImage"`
+}
+
+// State represents the UKI state of the machine.
+type State struct {
+	Stub       StubInfo    `yaml:"stub"`
+	Prediction *Prediction `yaml:"prediction"`
+	ActualPCR  string      `yaml:"-"`
+}
+
+// Attestable implements the report.Attestable interface for the UKI.
+type Attestable struct {
+	// Path is the path of the booted UKI on the host. When empty, the UKI is looked up through the
+	// LoaderImageIdentifier on the EFI system partition.
+	Path string
+
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "uki"
+}
+
+// Measure returns the measurement of the UKI and its predicted PCR 11 value.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetState(a.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get UKI state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal UKI state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the UKI and the expected versus actual PCR 11 values.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"stub_info":               a.state.Stub.Info,
+		"loader_image_identifier": a.state.Stub.ImageIdentifier,
+		"stub_pcr_kernel_image":   a.state.Stub.PCRKernelImage,
+		"actual_pcr11":            a.state.ActualPCR,
+		"uki_found":               "false",
+		"pcr11_match":             pcr11MatchNone,
+		"timestamp":               a.timestamp,
+	}
+
+	prediction := a.state.Prediction
+	if prediction == nil {
+		return evidence, nil
+	}
+
+	evidence["uki_found"] = "true"
+	evidence["uki_path"] = prediction.Path
+	evidence["expected_pcr11"] = prediction.PCR
+	evidence["pcrpkey"] = prediction.PCRPublicKey
+	evidence["pcrpkey_sha256"] = ""
+	evidence["pcr11_match"] = matchPCR(prediction, a.state.ActualPCR)
+
+	for _, section := range prediction.Sections {
+		key := "section_" + strings.TrimPrefix(section.Name, ".") + "_sha256"
+		evidence[key] = section.Digest
+
+		if section.Name == sectionPCRPublicKey {
+			evidence["pcrpkey_sha256"] = section.Digest
+		}
+	}
+
+	for _, phase := range prediction.Phases {
+		evidence["expected_pcr11_"+phase.Name] = phase.PCR
+	}
+
+	return evidence, nil
+}
+
+// GetState reads the systemd-stub EFI variables and predicts PCR 11 for the booted UKI, if it can be found
+// at the given path or on the EFI system partition.
+func GetState(ukiPath string) (*State, error) {
+	stub, err := GetStubInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := readActualPCR()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Stub:      *stub,
+		ActualPCR: actual,
+	}
+
+	path := findUKI(ukiPath, stub.ImageIdentifier)
+	if path == "" {
+		return state, nil
+	}
+
+	prediction, err := PredictPCR11(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to predict PCR 11: %w", err)
+	}
+
+	state.Prediction = prediction
+
+	return state, nil
+}
+
+// GetStubInfo reads the EFI variables set by systemd-stub. Missing variables are returned empty.
+func GetStubInfo() (*StubInfo, error) {
+	info, err := readEFIStringVar(stubInfoVar)
+	if err != nil {
+		return nil, err
+	}
+
+	imageIdentifier, err := readEFIStringVar(loaderImageIDVar)
+	if err != nil {
+		return nil, err
+	}
+
+	pcrKernelImage, err := readEFIStringVar(stubPCRKernelVar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StubInfo{
+		Info:            info,
+		ImageIdentifier: imageIdentifier,
+		PCRKernelImage:  pcrKernelImage,
+	}, nil
+}
+
+func readEFIStringVar(name string) (string, error) {
+	path := filepath.Join(efiVarsDir, name+"-"+loaderVendorGUID)
+
+	//nolint:gosec // EFI variable path is controlled
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read EFI variable %s: %w", name, err)
+	}
+
+	if len(data) < efiVarAttributesLen {
+		return "", nil
+	}
+
+	// Skip the attributes and decode the UTF-16LE payload.
+	payload := data[efiVarAttributesLen:]
+	chars := make([]uint16, 0, len(payload)/2)
+
+	for i := 0; i+1 < len(payload); i += 2 {
+		chars = append(chars, binary.LittleEndian.Uint16(payload[i:]))
+	}
+
+	return strings.TrimRight(string(utf16.Decode(chars)), "\x00"), nil
+}
+
+func readActualPCR() (string, error) {
+	data, err := os.ReadFile(pcr11Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read PCR 11: %w", err)
+	}
+
+	return strings.ToLower(strings.TrimSpace(string(data))), nil
+}
+
+// findUKI returns the path of the UKI in the extension container. The configured path is looked up first,
+// then the LoaderImageIdentifier relative to each possible EFI system partition mount point.
+func findUKI(ukiPath string, imageIdentifier string) string {
+	candidates := make([]string, 0, len(espMountPoints)+1)
+
+	if ukiPath != "" {
+		candidates = append(candidates, ukiPath)
+	}
+
+	if imageIdentifier != "" {
+		relative := strings.TrimPrefix(strings.ReplaceAll(imageIdentifier, `\`, "/"), "/")
+
+		for _, mountPoint := range espMountPoints {
+			candidates = append(candidates, filepath.Join(mountPoint, relative))
+		}
+	}
+
+	for _, candidate := range candidates {
+		for _, root := range searchRoots {
+			path := filepath.Join(root, candidate)
+
+			_, err := os.Stat(path)
+			if err == nil {
+				return path
+			}
+		}
+	}
+
+	return ""
+}
+
+func matchPCR(prediction *Prediction, actual string) string {
+	if actual == "" {
+		return pcr11MatchNone
+	}
+
+	if prediction.PCR == actual {
+		return pcr11MatchSections
+	}
+
+	for _, phase := range prediction.Phases {
+		if phase.PCR == actual {
+			return phase.Name
+		}
+	}
+
+	return pcr11MatchNone
+}
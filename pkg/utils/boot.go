@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Uptime returns the time elapsed since boot, including the time the machine was suspended.
+func Uptime() (time.Duration, error) {
+	var uptime unix.Timespec
+
+	err := unix.ClockGettime(unix.CLOCK_BOOTTIME, &uptime)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read uptime: %w", err)
+	}
+
+	return time.Duration(uptime.Nano()), nil
+}
+
+// BootTime returns the wall clock time of the boot.
+func BootTime() (time.Time, error) {
+	uptime, err := Uptime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().Add(-uptime), nil
+}
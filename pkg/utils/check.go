@@ -0,0 +1,32 @@
+package utils
+
+const (
+	// ResultPass is reported when the check passed.
+	ResultPass = "pass"
+	// ResultFail is reported when the check failed; the check carries the reason.
+	ResultFail = "fail"
+	// ResultSkipped is reported when the machine does not expose the data for the check.
+	ResultSkipped = "skipped"
+)
+
+// CheckResult represents the result of a single policy check.
+type CheckResult struct {
+	Name   string `yaml:"name"`
+	Result string `yaml:"result"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// Pass returns a passed check.
+func Pass(name string) CheckResult {
+	return CheckResult{Name: name, Result: ResultPass}
+}
+
+// Fail returns a failed check with the reason.
+func Fail(name string, reason string) CheckResult {
+	return CheckResult{Name: name, Result: ResultFail, Reason: reason}
+}
+
+// Skip returns a skipped check with the reason.
+func Skip(name string, reason string) CheckResult {
+	return CheckResult{Name: name, Result: ResultSkipped, Reason: reason}
+}
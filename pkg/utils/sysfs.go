@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadSysfsValue returns the trimmed content of a sysfs, procfs or securityfs attribute, or an empty string
+// when the attribute cannot be read.
+func ReadSysfsValue(path string) string {
+	value, err := ReadValue(path)
+	if err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// ReadValue returns the trimmed content of a sysfs, procfs or securityfs attribute.
+func ReadValue(path string) (string, error) {
+	//nolint:gosec // Pseudo-filesystem paths are controlled
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// GetDriver returns the name of the driver bound to the sysfs device, or an empty string when none is bound.
+func GetDriver(devicePath string) string {
+	driver, err := os.Readlink(filepath.Join(devicePath, "driver"))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(driver)
+}
+
+// CString returns the string up to the first NUL byte of a fixed-size C string.
+func CString(data []byte) string {
+	if end := strings.IndexByte(string(data), 0); end >= 0 {
+		return string(data[:end])
+	}
+
+	return string(data)
+}
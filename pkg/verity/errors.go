@@ -0,0 +1,9 @@
+// Package verity provides error definitions for root filesystem integrity operations.
+package verity
+
+import "errors"
+
+var (
+	// ErrRootMountNotFound is returned when the root mount cannot be found in the mount table.
+	ErrRootMountNotFound = errors.New("root mount not found")
+)
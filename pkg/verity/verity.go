@@ -0,0 +1,292 @@
+// Package verity provides utilities to attest the integrity of the root filesystem image of the Talos machine.
+package verity
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/devicemapper"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/mounts"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	sysDevBlockDir         = "/sys/dev/block"
+	verityTargetType       = "verity"
+	verityMinParams        = 10
+	verityStatusCorrupted  = "C"
+	squashfsMagic          = 0x73717368 // "hsqs"
+	squashfsSuperblockSize = 96
+
+	// ModeVerity is reported when the root filesystem is backed by a dm-verity target.
+	ModeVerity = "verity"
+	// ModeSquashfs is reported when the root filesystem is a squashfs image without dm-verity.
+	ModeSquashfs = "squashfs"
+	// ModeUnknown is reported when the root filesystem is neither dm-verity nor squashfs.
+	ModeUnknown = "unknown"
+)
+
+//nolint:gochecknoglobals
+var squashfsCompressions = map[uint16]string{
+	1: "gzip",
+	2: "lzma",
+	3: "lzo",
+	4: "xz",
+	5: "lz4",
+	6: "zstd",
+}
+
+// State represents the integrity state of the root filesystem.
+type State struct {
+	Mode       string      `yaml:"mode"`
+	RootDevice string      `yaml:"rootDevice"`
+	Verity     *Target     `yaml:"verity,omitempty"`
+	Squashfs   *Superblock `yaml:"squashfs,omitempty"`
+}
+
+// Target represents the dm-verity target behind the root mount.
+type Target struct {
+	Device        string `yaml:"device"`
+	RootHash      string `yaml:"rootHash"`
+	HashAlgorithm string `yaml:"hashAlgorithm"`
+	Salt          string `yaml:"salt"`
+	Status        string `yaml:"-"`
+}
+
+// Superblock represents the identifying fields of a squashfs superblock.
+type Superblock struct {
+	Device      string `yaml:"device"`
+	BackingFile string `yaml:"backingFile"`
+	Digest      string `yaml:"digest"`
+	Compression string `yaml:"compression"`
+	Version     string `yaml:"version"`
+	BlockSize   uint32 `yaml:"blockSize"`
+	InodeCount  uint32 `yaml:"inodeCount"`
+	BytesUsed   uint64 `yaml:"bytesUsed"`
+	MkfsTime    uint32 `yaml:"mkfsTime"`
+}
+
+// Attestable implements the report.Attestable interface for the root filesystem integrity.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "dm-verity"
+}
+
+// Measure returns the measurement of the root filesystem identity.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetRootIntegrity()
+	if err != nil {
+		return "", fmt.Errorf("failed to get root filesystem integrity: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal root filesystem integrity: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the root filesystem integrity.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"mode":        a.state.Mode,
+		"root_device": a.state.RootDevice,
+		"timestamp":   a.timestamp,
+	}
+
+	if target := a.state.Verity; target != nil {
+		evidence["verity_device"] = target.Device
+		evidence["verity_root_hash"] = target.RootHash
+		evidence["verity_hash_algorithm"] = target.HashAlgorithm
+		evidence["verity_salt"] = target.Salt
+		evidence["verity_status"] = target.Status
+		evidence["verity_corrupted"] = strconv.FormatBool(target.Status == verityStatusCorrupted)
+	}
+
+	if superblock := a.state.Squashfs; superblock != nil {
+		evidence["squashfs_device"] = superblock.Device
+		evidence["squashfs_backing_file"] = superblock.BackingFile
+		evidence["squashfs_superblock_hash"] = superblock.Digest
+		evidence["squashfs_compression"] = superblock.Compression
+		evidence["squashfs_version"] = superblock.Version
+		evidence["squashfs_block_size"] = strconv.FormatUint(uint64(superblock.BlockSize), 10)
+		evidence["squashfs_inode_count"] = strconv.FormatUint(uint64(superblock.InodeCount), 10)
+		evidence["squashfs_bytes_used"] = strconv.FormatUint(superblock.BytesUsed, 10)
+		evidence["squashfs_mkfs_time"] = strconv.FormatUint(uint64(superblock.MkfsTime), 10)
+	}
+
+	return evidence, nil
+}
+
+// GetRootIntegrity finds the device behind the root mount and reports its dm-verity target, or the
+// squashfs superblock when dm-verity is not used.
+func GetRootIntegrity() (*State, error) {
+	rootDevice, err := getRootDevice()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Mode:       ModeUnknown,
+		RootDevice: rootDevice,
+	}
+
+	target, err := getVerityTarget(rootDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	if target != nil {
+		state.Mode = ModeVerity
+		state.Verity = target
+
+		return state, nil
+	}
+
+	superblock, err := readSquashfsSuperblock(rootDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	if superblock != nil {
+		state.Mode = ModeSquashfs
+		state.Squashfs = superblock
+	}
+
+	return state, nil
+}
+
+// getRootDevice returns the major:minor of the device behind the root mount of the host.
+func getRootDevice() (string, error) {
+	hostMounts, err := mounts.GetMounts()
+	if err != nil {
+		return "", fmt.Errorf("failed to get mounts: %w", err)
+	}
+
+	root, ok := mounts.EffectiveMount(hostMounts, "/")
+	if !ok {
+		return "", ErrRootMountNotFound
+	}
+
+	return root.Device, nil
+}
+
+func getVerityTarget(rootDevice string) (*Target, error) {
+	devices, err := devicemapper.ListDevices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device-mapper devices: %w", err)
+	}
+
+	for _, device := range devices {
+		if device.Dev != rootDevice {
+			continue
+		}
+
+		table, err := devicemapper.Table(device.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table of %s: %w", device.Name, err)
+		}
+
+		for i, target := range table {
+			params := strings.Fields(target.Params)
+			if target.Type != verityTargetType || len(params) < verityMinParams {
+				continue
+			}
+
+			status, err := devicemapper.Status(device.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read status of %s: %w", device.Name, err)
+			}
+
+			verity := &Target{
+				Device:        device.Name,
+				HashAlgorithm: params[7],
+				RootHash:      params[8],
+				Salt:          params[9],
+			}
+
+			if i < len(status) {
+				verity.Status = status[i].Params
+			}
+
+			return verity, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // Root is not backed by dm-verity
+}
+
+func readSquashfsSuperblock(rootDevice string) (*Superblock, error) {
+	devName := getDevName(rootDevice)
+	if devName == "" {
+		return nil, nil //nolint:nilnil // Root is not backed by a block device
+	}
+
+	devicePath := filepath.Join("/dev", devName)
+
+	//nolint:gosec // Device path is resolved from sysfs
+	file, err := os.Open(devicePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open root device %s: %w", devicePath, err)
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	data := make([]byte, squashfsSuperblockSize)
+
+	_, err = io.ReadFull(file, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read superblock of %s: %w", devicePath, err)
+	}
+
+	if binary.LittleEndian.Uint32(data[0:4]) != squashfsMagic {
+		return nil, nil //nolint:nilnil // Root device is not squashfs
+	}
+
+	compression, ok := squashfsCompressions[binary.LittleEndian.Uint16(data[20:22])]
+	if !ok {
+		compression = "unknown"
+	}
+
+	return &Superblock{
+		Device:      devicePath,
+		BackingFile: utils.ReadSysfsValue(filepath.Join(sysDevBlockDir, rootDevice, "loop", "backing_file")),
+		Digest:      utils.EncodeMeasurement(data),
+		Compression: compression,
+		Version: fmt.Sprintf("%d.%d",
+			binary.LittleEndian.Uint16(data[28:30]), binary.LittleEndian.Uint16(data[30:32])),
+		BlockSize:  binary.LittleEndian.Uint32(data[12:16]),
+		InodeCount: binary.LittleEndian.Uint32(data[4:8]),
+		BytesUsed:  binary.LittleEndian.Uint64(data[40:48]),
+		MkfsTime:   binary.LittleEndian.Uint32(data[8:12]),
+	}, nil
+}
+
+func getDevName(device string) string {
+	uevent := utils.ReadSysfsValue(filepath.Join(sysDevBlockDir, device, "uevent"))
+
+	for line := range strings.SplitSeq(uevent, "\n") {
+		if name, ok := strings.CutPrefix(line, "DEVNAME="); ok {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,236 @@
+// Package vulnerabilities provides utilities to attest the CPU vulnerability mitigations and microcode of the machine.
+package vulnerabilities
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/cpuinfo"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	vulnerabilitiesDir   = "/sys/devices/system/cpu/vulnerabilities"
+	cpuDir               = "/sys/devices/system/cpu"
+	smtControlPath       = cpuDir + "/smt/control"
+	smtActivePath        = cpuDir + "/smt/active"
+	microcodeVersionGlob = cpuDir + "/cpu[0-9]*/microcode/version"
+	smtVulnerableMarker  = "SMT vulnerable"
+
+	// StatusMitigated is reported when the kernel mitigates the vulnerability.
+	StatusMitigated = "mitigated"
+	// StatusVulnerable is reported when the CPU is affected and the vulnerability is not mitigated.
+	StatusVulnerable = "vulnerable"
+	// StatusNotAffected is reported when the CPU is not affected by the vulnerability.
+	StatusNotAffected = "not-affected"
+	// StatusUnknown is reported when the kernel reports a state that cannot be classified.
+	StatusUnknown = "unknown"
+)
+
+// State represents the CPU vulnerability and microcode state of the machine.
+type State struct {
+	CPU             CPU             `yaml:"cpu"`
+	Microcode       []string        `yaml:"microcode"`
+	SMTControl      string          `yaml:"smtControl"`
+	SMTActive       bool            `yaml:"smtActive"`
+	Vulnerabilities []Vulnerability `yaml:"vulnerabilities"`
+}
+
+// CPU represents the identity of the CPU model.
+type CPU struct {
+	Vendor    string `yaml:"vendor"`
+	ModelName string `yaml:"modelName"`
+	Family    string `yaml:"family"`
+	Model     string `yaml:"model"`
+	Stepping  string `yaml:"stepping"`
+}
+
+// Vulnerability represents the kernel reported state of a single CPU vulnerability.
+type Vulnerability struct {
+	Name          string `yaml:"name"`
+	Status        string `yaml:"status"`
+	Detail        string `yaml:"detail"`
+	SMTVulnerable bool   `yaml:"smtVulnerable"`
+}
+
+// Attestable implements the report.Attestable interface for CPU vulnerabilities.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "cpu-vulnerabilities"
+}
+
+// Measure returns the measurement of the CPU vulnerability and microcode state.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetVulnerabilityState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get CPU vulnerability state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal CPU vulnerability state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about the CPU vulnerabilities and microcode.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"cpu_vendor":            a.state.CPU.Vendor,
+		"cpu_model_name":        a.state.CPU.ModelName,
+		"cpu_family":            a.state.CPU.Family,
+		"cpu_model":             a.state.CPU.Model,
+		"cpu_stepping":          a.state.CPU.Stepping,
+		"microcode_revision":    strings.Join(a.state.Microcode, ","),
+		"microcode_consistent":  strconv.FormatBool(len(a.state.Microcode) <= 1),
+		"smt_control":           a.state.SMTControl,
+		"smt_active":            strconv.FormatBool(a.state.SMTActive),
+		"vulnerabilities_count": strconv.Itoa(len(a.state.Vulnerabilities)),
+		"timestamp":             a.timestamp,
+	}
+
+	counts := make(map[string]int)
+	smtVulnerable := make([]string, 0)
+
+	for _, vulnerability := range a.state.Vulnerabilities {
+		evidence["vulnerability_"+vulnerability.Name] = vulnerability.Status
+		evidence["vulnerability_"+vulnerability.Name+"_detail"] = vulnerability.Detail
+		counts[vulnerability.Status]++
+
+		if vulnerability.SMTVulnerable {
+			smtVulnerable = append(smtVulnerable, vulnerability.Name)
+		}
+	}
+
+	evidence["mitigated_count"] = strconv.Itoa(counts[StatusMitigated])
+	evidence["vulnerable_count"] = strconv.Itoa(counts[StatusVulnerable])
+	evidence["not_affected_count"] = strconv.Itoa(counts[StatusNotAffected])
+	evidence["smt_vulnerable"] = strings.Join(smtVulnerable, ",")
+
+	return evidence, nil
+}
+
+// GetVulnerabilityState reads the CPU identity, microcode revisions and kernel reported vulnerabilities.
+func GetVulnerabilityState() (*State, error) {
+	processors, err := cpuinfo.ParseProcCPUInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpuinfo: %w", err)
+	}
+
+	vulnerabilities, err := getVulnerabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Microcode:       getMicrocodeRevisions(processors),
+		SMTControl:      utils.ReadSysfsValue(smtControlPath),
+		SMTActive:       utils.ReadSysfsValue(smtActivePath) == "1",
+		Vulnerabilities: vulnerabilities,
+	}
+
+	if len(processors) > 0 {
+		state.CPU = CPU{
+			Vendor:    processors[0]["vendor_id"],
+			ModelName: processors[0]["model name"],
+			Family:    processors[0]["cpu family"],
+			Model:     processors[0]["model"],
+			Stepping:  processors[0]["stepping"],
+		}
+	}
+
+	return state, nil
+}
+
+func getVulnerabilities() ([]Vulnerability, error) {
+	entries, err := os.ReadDir(vulnerabilitiesDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Vulnerability{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", vulnerabilitiesDir, err)
+	}
+
+	vulnerabilities := make([]Vulnerability, 0, len(entries))
+
+	for _, entry := range entries {
+		detail := utils.ReadSysfsValue(filepath.Join(vulnerabilitiesDir, entry.Name()))
+
+		vulnerabilities = append(vulnerabilities, Vulnerability{
+			Name:          entry.Name(),
+			Status:        classify(detail),
+			Detail:        detail,
+			SMTVulnerable: strings.Contains(detail, smtVulnerableMarker),
+		})
+	}
+
+	return vulnerabilities, nil
+}
+
+// classify maps the kernel reported vulnerability state to a status.
+func classify(detail string) string {
+	// Some entries are prefixed with the affected subsystem, e.g. "KVM: Mitigation: VMX disabled".
+	detail = strings.TrimPrefix(detail, "KVM: ")
+
+	switch {
+	case strings.HasPrefix(detail, "Not affected"):
+		return StatusNotAffected
+	case strings.HasPrefix(detail, "Vulnerable"):
+		return StatusVulnerable
+	case strings.HasPrefix(detail, "Mitigation"):
+		return StatusMitigated
+	default:
+		return StatusUnknown
+	}
+}
+
+// getMicrocodeRevisions returns the distinct microcode revisions reported by cpuinfo and sysfs.
+// More than one revision means the CPUs were not updated consistently.
+func getMicrocodeRevisions(processors []cpuinfo.Processor) []string {
+	revisions := make(map[string]bool)
+
+	for _, processor := range processors {
+		if revision, ok := processor["microcode"]; ok {
+			revisions[normalizeRevision(revision)] = true
+		}
+	}
+
+	paths, _ := filepath.Glob(microcodeVersionGlob)
+	for _, path := range paths {
+		if revision := utils.ReadSysfsValue(path); revision != "" {
+			revisions[normalizeRevision(revision)] = true
+		}
+	}
+
+	result := make([]string, 0, len(revisions))
+	for revision := range revisions {
+		result = append(result, revision)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func normalizeRevision(revision string) string {
+	value, err := strconv.ParseUint(strings.TrimPrefix(revision, "0x"), 16, 64)
+	if err != nil {
+		return revision
+	}
+
+	return "0x" + strconv.FormatUint(value, 16)
+}
@@ -0,0 +1,10 @@
+// Package wireguard provides error definitions for WireGuard netlink operations.
+package wireguard
+
+import "errors"
+
+var (
+	// ErrFamilyNotFound is returned when the WireGuard generic netlink family is not registered, because
+	// the WireGuard module is not loaded.
+	ErrFamilyNotFound = errors.New("WireGuard generic netlink family not found")
+)
@@ -0,0 +1,277 @@
+package wireguard
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	sysClassNetPath    = "/sys/class/net"
+	ueventDevType      = "DEVTYPE=wireguard"
+	genlHeaderSize     = 4
+	controlVersion     = 1
+	timespecSize       = 16
+	sockaddrPortOffset = 2
+	sockaddr4AddrStart = 4
+	sockaddr6AddrStart = 8
+	sockaddr4Size      = 16
+	sockaddr6Size      = 28
+)
+
+// conn is a generic netlink connection to the WireGuard family.
+type conn struct {
+	*netlink.Conn
+	family uint16
+}
+
+// dial connects to generic netlink and resolves the WireGuard family.
+func dial() (*conn, error) {
+	c, err := netlink.Dial(unix.NETLINK_GENERIC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to generic netlink: %w", err)
+	}
+
+	// The family dump lists every registered family, of which the WireGuard family is looked up by name.
+	payloads, err := c.Dump(unix.GENL_ID_CTRL, genlHeader(unix.CTRL_CMD_GETFAMILY, controlVersion), nil)
+	if err != nil {
+		c.Close()
+
+		return nil, fmt.Errorf("failed to resolve the %s generic netlink family: %w", unix.WG_GENL_NAME, err)
+	}
+
+	for _, payload := range payloads {
+		attributes, err := parsePayload(payload)
+		if err != nil {
+			c.Close()
+
+			return nil, err
+		}
+
+		if netlink.FindString(attributes, unix.CTRL_ATTR_FAMILY_NAME) != unix.WG_GENL_NAME {
+			continue
+		}
+
+		family, ok := netlink.FindUint16(attributes, unix.CTRL_ATTR_FAMILY_ID, binary.NativeEndian)
+		if ok {
+			return &conn{Conn: c, family: family}, nil
+		}
+	}
+
+	c.Close()
+
+	return nil, fmt.Errorf("%w: %s", ErrFamilyNotFound, unix.WG_GENL_NAME)
+}
+
+// getDevice returns the WireGuard interface. Devices with many peers are split across multiple messages,
+// which are merged. A peer with many allowed IPs is continued in the next message by repeating the peer
+// with only its public key and the remaining allowed IPs.
+func (c *conn) getDevice(name string) (*Interface, error) {
+	payloads, err := c.Dump(c.family, genlHeader(unix.WG_CMD_GET_DEVICE, unix.WG_GENL_VERSION), []netlink.Attribute{
+		netlink.StringAttribute(unix.WGDEVICE_A_IFNAME, name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WireGuard device %s: %w", name, err)
+	}
+
+	iface := &Interface{
+		Name:  name,
+		Peers: make([]Peer, 0),
+	}
+
+	for _, payload := range payloads {
+		attributes, err := parsePayload(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, ok := netlink.Find(attributes, unix.WGDEVICE_A_PUBLIC_KEY)
+		if ok {
+			iface.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+		}
+
+		port, ok := netlink.FindUint16(attributes, unix.WGDEVICE_A_LISTEN_PORT, binary.NativeEndian)
+		if ok {
+			iface.ListenPort = port
+		}
+
+		peers, err := netlink.FindNested(attributes, unix.WGDEVICE_A_PEERS)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range peers {
+			peer, err := parsePeer(item.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			last := len(iface.Peers) - 1
+			if last >= 0 && iface.Peers[last].PublicKey == peer.PublicKey {
+				iface.Peers[last].AllowedIPs = append(iface.Peers[last].AllowedIPs, peer.AllowedIPs...)
+
+				continue
+			}
+
+			iface.Peers = append(iface.Peers, peer)
+		}
+	}
+
+	return iface, nil
+}
+
+func parsePeer(data []byte) (Peer, error) {
+	attributes, err := netlink.ParseAttributes(data)
+	if err != nil {
+		return Peer{}, err
+	}
+
+	peer := Peer{
+		AllowedIPs: make([]string, 0),
+	}
+
+	publicKey, ok := netlink.Find(attributes, unix.WGPEER_A_PUBLIC_KEY)
+	if ok {
+		peer.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	}
+
+	endpoint, ok := netlink.Find(attributes, unix.WGPEER_A_ENDPOINT)
+	if ok {
+		peer.Endpoint = parseSockaddr(endpoint)
+	}
+
+	handshake, ok := netlink.Find(attributes, unix.WGPEER_A_LAST_HANDSHAKE_TIME)
+	if ok && len(handshake) >= timespecSize {
+		//nolint:gosec // struct __kernel_timespec holds signed 64-bit values
+		seconds := int64(binary.NativeEndian.Uint64(handshake[0:8]))
+		if seconds != 0 {
+			peer.LastHandshake = strconv.FormatInt(seconds, 10)
+		}
+	}
+
+	allowedIPs, err := netlink.FindNested(attributes, unix.WGPEER_A_ALLOWEDIPS)
+	if err != nil {
+		return Peer{}, err
+	}
+
+	for _, item := range allowedIPs {
+		prefix, err := parseAllowedIP(item.Data)
+		if err != nil {
+			return Peer{}, err
+		}
+
+		peer.AllowedIPs = append(peer.AllowedIPs, prefix)
+	}
+
+	return peer, nil
+}
+
+func parseAllowedIP(data []byte) (string, error) {
+	attributes, err := netlink.ParseAttributes(data)
+	if err != nil {
+		return "", err
+	}
+
+	raw, _ := netlink.Find(attributes, unix.WGALLOWEDIP_A_IPADDR)
+
+	address, ok := netip.AddrFromSlice(raw)
+	if !ok {
+		return "", fmt.Errorf("%w: allowed IP of length %d", netlink.ErrInvalidMessage, len(raw))
+	}
+
+	mask, _ := netlink.Find(attributes, unix.WGALLOWEDIP_A_CIDR_MASK)
+	if len(mask) == 0 {
+		return "", fmt.Errorf("%w: allowed IP without CIDR mask", netlink.ErrInvalidMessage)
+	}
+
+	return netip.PrefixFrom(address, int(mask[0])).String(), nil
+}
+
+// parseSockaddr returns the address and port of a struct sockaddr_in or sockaddr_in6.
+func parseSockaddr(data []byte) string {
+	if len(data) < sockaddr4Size {
+		return ""
+	}
+
+	port := binary.BigEndian.Uint16(data[sockaddrPortOffset:])
+
+	switch binary.NativeEndian.Uint16(data[0:2]) {
+	case unix.AF_INET:
+		ip := net.IP(data[sockaddr4AddrStart : sockaddr4AddrStart+net.IPv4len])
+
+		return net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+	case unix.AF_INET6:
+		if len(data) < sockaddr6Size {
+			return ""
+		}
+
+		ip := net.IP(data[sockaddr6AddrStart : sockaddr6AddrStart+net.IPv6len])
+
+		return net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+	default:
+		return ""
+	}
+}
+
+// listInterfaces returns the names of the WireGuard interfaces, which report the wireguard device type.
+func listInterfaces() ([]string, error) {
+	entries, err := os.ReadDir(sysClassNetPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sysClassNetPath, err)
+	}
+
+	names := make([]string, 0)
+
+	for _, entry := range entries {
+		if isWireGuard(filepath.Join(sysClassNetPath, entry.Name(), "uevent")) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func isWireGuard(ueventPath string) bool {
+	file, err := os.Open(ueventPath)
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == ueventDevType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func genlHeader(command uint8, version uint8) []byte {
+	return []byte{command, version, 0, 0}
+}
+
+func parsePayload(payload []byte) ([]netlink.Attribute, error) {
+	if len(payload) < genlHeaderSize {
+		return nil, fmt.Errorf("%w: truncated generic netlink header", netlink.ErrInvalidMessage)
+	}
+
+	return netlink.ParseAttributes(payload[genlHeaderSize:])
+}
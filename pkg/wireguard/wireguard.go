@@ -0,0 +1,146 @@
+// Package wireguard provides utilities to attest the WireGuard interfaces of the machine, including KubeSpan.
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/netlink"
+	"github.com/kommodity-io/kommodity-attestation-extension/pkg/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// KubeSpanInterface is the name of the WireGuard interface Talos creates for KubeSpan.
+	KubeSpanInterface = "kubespan"
+)
+
+// Peer represents a WireGuard peer. Preshared keys are never read.
+type Peer struct {
+	PublicKey     string   `yaml:"publicKey"`
+	AllowedIPs    []string `yaml:"allowedIPs"`
+	Endpoint      string   `yaml:"-"`
+	LastHandshake string   `yaml:"-"`
+}
+
+// Interface represents a WireGuard interface. The private key is never read.
+type Interface struct {
+	Name       string `yaml:"name"`
+	PublicKey  string `yaml:"publicKey"`
+	ListenPort uint16 `yaml:"listenPort"`
+	Peers      []Peer `yaml:"peers"`
+}
+
+// State represents the WireGuard interfaces of the machine.
+type State struct {
+	Available  bool        `yaml:"available"`
+	Interfaces []Interface `yaml:"interfaces"`
+}
+
+// Attestable implements the report.Attestable interface for the WireGuard interfaces.
+type Attestable struct {
+	state     *State
+	timestamp string
+}
+
+// Name returns the name of the attestable component.
+func (a *Attestable) Name() string {
+	return "wireguard"
+}
+
+// Measure returns the measurement of the WireGuard identities and peers. Handshake times and endpoints
+// change at runtime and are only reported as evidence.
+func (a *Attestable) Measure() (string, error) {
+	state, err := GetWireGuardState()
+	if err != nil {
+		return "", fmt.Errorf("failed to get WireGuard state: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal WireGuard state: %w", err)
+	}
+
+	a.timestamp = utils.UnixNowString()
+	a.state = state
+
+	return utils.EncodeMeasurement(data), nil
+}
+
+// Evidence returns metadata about each WireGuard interface and its peers.
+func (a *Attestable) Evidence() (map[string]string, error) {
+	evidence := map[string]string{
+		"available":        strconv.FormatBool(a.state.Available),
+		"interfaces_count": strconv.Itoa(len(a.state.Interfaces)),
+		"kubespan_enabled": "false",
+		"timestamp":        a.timestamp,
+	}
+
+	for _, iface := range a.state.Interfaces {
+		prefix := "interface_" + iface.Name + "_"
+		evidence[prefix+"public_key"] = iface.PublicKey
+		evidence[prefix+"listen_port"] = strconv.FormatUint(uint64(iface.ListenPort), 10)
+		evidence[prefix+"peers_count"] = strconv.Itoa(len(iface.Peers))
+
+		if iface.Name == KubeSpanInterface {
+			evidence["kubespan_enabled"] = "true"
+			evidence["kubespan_public_key"] = iface.PublicKey
+		}
+
+		for i, peer := range iface.Peers {
+			peerPrefix := fmt.Sprintf("%speer_%d_", prefix, i)
+			evidence[peerPrefix+"public_key"] = peer.PublicKey
+			evidence[peerPrefix+"allowed_ips"] = strings.Join(peer.AllowedIPs, ",")
+			evidence[peerPrefix+"endpoint"] = peer.Endpoint
+			evidence[peerPrefix+"last_handshake"] = peer.LastHandshake
+		}
+	}
+
+	return evidence, nil
+}
+
+// GetWireGuardState enumerates the WireGuard interfaces over generic netlink. Interfaces are sorted by name,
+// peers by public key and allowed IPs by prefix. When WireGuard is not available, it is reported as unavailable.
+func GetWireGuardState() (*State, error) {
+	state := &State{
+		Interfaces: make([]Interface, 0),
+	}
+
+	c, err := dial()
+	if netlink.IsUnavailable(err) || errors.Is(err, ErrFamilyNotFound) {
+		return state, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer c.Close()
+
+	state.Available = true
+
+	names, err := listInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		iface, err := c.getDevice(name)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Slice(iface.Peers, func(i, j int) bool {
+			return iface.Peers[i].PublicKey < iface.Peers[j].PublicKey
+		})
+
+		for _, peer := range iface.Peers {
+			sort.Strings(peer.AllowedIPs)
+		}
+
+		state.Interfaces = append(state.Interfaces, *iface)
+	}
+
+	return state, nil
+}